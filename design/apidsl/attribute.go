@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -389,10 +390,22 @@ func Pattern(p string) {
 
 // Minimum adds a "minimum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor21.
+// The minimum of a Duration attribute is given as a time.Duration or as a string accepted by
+// time.ParseDuration, e.g. "1s".
 func Minimum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
-			incompatibleAttributeType("minimum", a.Type.Name(), "an integer or a number")
+		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind && a.Type.Kind() != design.DurationKind {
+			incompatibleAttributeType("minimum", a.Type.Name(), "an integer, a number or a duration")
+		} else if a.Type != nil && a.Type.Kind() == design.DurationKind {
+			f, ok := durationValue(val)
+			if !ok {
+				dslengine.ReportError("invalid duration value %#v", val)
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.Minimum = &f
 		} else {
 			var f float64
 			switch v := val.(type) {
@@ -419,10 +432,22 @@ func Minimum(val interface{}) {
 
 // Maximum adds a "maximum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+// The maximum of a Duration attribute is given as a time.Duration or as a string accepted by
+// time.ParseDuration, e.g. "1h".
 func Maximum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
-			incompatibleAttributeType("maximum", a.Type.Name(), "an integer or a number")
+		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind && a.Type.Kind() != design.DurationKind {
+			incompatibleAttributeType("maximum", a.Type.Name(), "an integer, a number or a duration")
+		} else if a.Type != nil && a.Type.Kind() == design.DurationKind {
+			f, ok := durationValue(val)
+			if !ok {
+				dslengine.ReportError("invalid duration value %#v", val)
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.Maximum = &f
 		} else {
 			var f float64
 			switch v := val.(type) {
@@ -447,6 +472,22 @@ func Maximum(val interface{}) {
 	}
 }
 
+// durationValue returns the number of nanoseconds of the given duration value which must be a
+// time.Duration or a string accepted by time.ParseDuration.
+func durationValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case time.Duration:
+		return float64(v), true
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, false
+		}
+		return float64(d), true
+	}
+	return 0, false
+}
+
 // MinLength adss a "minItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
 func MinLength(val int) {
@@ -477,6 +518,22 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueItems adds a "uniqueItems" validation to the attribute. Array attributes whose items are
+// unique may be generated as sets, see codegen.GoSetType.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor49.
+func UniqueItems() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.ArrayKind {
+			incompatibleAttributeType("unique items", a.Type.Name(), "an array")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueItems = true
+		}
+	}
+}
+
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
@@ -254,6 +254,37 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name, an array type and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = ArrayOf(String)
+			dsl = func() {
+				UniqueItems()
+			}
+		})
+
+		It("records the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.UniqueItems).Should(BeTrue())
+		})
+	})
+
+	Context("with a name, a string type and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				UniqueItems()
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name and type uuid", func() {
 		BeforeEach(func() {
 			name = "birthdate"
@@ -367,24 +367,24 @@ var _ = Describe("Example", func() {
 			mt := MediaType("application/vnd.example+json", func() {
 				Attributes(func() {
 					Attribute("test1", HashOf(String, Integer))
-					Attribute("test2", HashOf(Any, String))
+					Attribute("test2", HashOf(Integer, String))
 					Attribute("test3", HashOf(String, Any))
-					Attribute("test4", HashOf(Any, Any))
+					Attribute("test4", HashOf(Integer, Any))
 
 					Attribute("test-with-user-type-1", HashOf(String, ut))
-					Attribute("test-with-user-type-2", HashOf(Any, ut))
+					Attribute("test-with-user-type-2", HashOf(Integer, ut))
 
 					Attribute("test-with-array-1", HashOf(String, ArrayOf(Integer)))
 					Attribute("test-with-array-2", HashOf(String, ArrayOf(Any)))
 					Attribute("test-with-array-3", HashOf(String, ArrayOf(ut)))
-					Attribute("test-with-array-4", HashOf(Any, ArrayOf(String)))
-					Attribute("test-with-array-5", HashOf(Any, ArrayOf(Any)))
-					Attribute("test-with-array-6", HashOf(Any, ArrayOf(ut)))
+					Attribute("test-with-array-4", HashOf(Integer, ArrayOf(String)))
+					Attribute("test-with-array-5", HashOf(Integer, ArrayOf(Any)))
+					Attribute("test-with-array-6", HashOf(Integer, ArrayOf(ut)))
 
 					Attribute("test-with-example-1", HashOf(String, Boolean), func() {
 						Example(map[string]bool{})
 					})
-					Attribute("test-with-example-2", HashOf(Any, Boolean), func() {
+					Attribute("test-with-example-2", HashOf(Integer, Boolean), func() {
 						Example(map[string]int{})
 					})
 				})
@@ -400,11 +400,11 @@ var _ = Describe("Example", func() {
 			attr := mt.Type.ToObject()["test1"]
 			Expect(attr.Example).Should(BeAssignableToTypeOf(map[string]int{}))
 			attr = mt.Type.ToObject()["test2"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}]string{}))
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int]string{}))
 			attr = mt.Type.ToObject()["test3"]
 			Expect(attr.Example).Should(BeAssignableToTypeOf(map[string]interface{}{}))
 			attr = mt.Type.ToObject()["test4"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}]interface{}{}))
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int]interface{}{}))
 
 			attr = mt.Type.ToObject()["test-with-user-type-1"]
 			Expect(attr.Example).Should(BeAssignableToTypeOf(map[string]map[string]interface{}{}))
@@ -414,8 +414,8 @@ var _ = Describe("Example", func() {
 				Expect(utattr["test1"]).Should(BeAssignableToTypeOf(int(0)))
 			}
 			attr = mt.Type.ToObject()["test-with-user-type-2"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}]map[string]interface{}{}))
-			for _, utattr := range attr.Example.(map[interface{}]map[string]interface{}) {
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int]map[string]interface{}{}))
+			for _, utattr := range attr.Example.(map[int]map[string]interface{}) {
 				Expect(utattr).Should(HaveKey("test1"))
 				Expect(utattr).Should(HaveKey("test2"))
 				Expect(utattr["test1"]).Should(BeAssignableToTypeOf(int(0)))
@@ -428,11 +428,11 @@ var _ = Describe("Example", func() {
 			attr = mt.Type.ToObject()["test-with-array-3"]
 			Expect(attr.Example).Should(BeAssignableToTypeOf(map[string][]map[string]interface{}{}))
 			attr = mt.Type.ToObject()["test-with-array-4"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}][]string{}))
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int][]string{}))
 			attr = mt.Type.ToObject()["test-with-array-5"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}][]interface{}{}))
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int][]interface{}{}))
 			attr = mt.Type.ToObject()["test-with-array-6"]
-			Expect(attr.Example).Should(BeAssignableToTypeOf(map[interface{}][]map[string]interface{}{}))
+			Expect(attr.Example).Should(BeAssignableToTypeOf(map[int][]map[string]interface{}{}))
 
 			attr = mt.Type.ToObject()["test-with-example-1"]
 			Expect(attr.Example).Should(BeAssignableToTypeOf(map[string]bool{}))
@@ -26,6 +26,49 @@ import (
 //        Metadata("struct:tag:json", "myName,omitempty")
 //        Metadata("struct:tag:xml", "myName,attr")
 //
+// `struct:tag:json:alias`: lists alternate JSON keys the attribute value is read from when the
+// canonical key is missing, the canonical key is always used when encoding. Useful to rename
+// attributes in a backward compatible way.
+// Applicable to attributes of user types and media types only.
+//
+//        Metadata("struct:tag:json:alias", "oldName")
+//
+// `struct:field:capacity`: sets the capacity of the slices allocated by the generated decoding
+// code for the attribute, the value must be a non-negative integer.
+// Applicable to attributes of array types only.
+//
+//        Metadata("struct:field:capacity", "16")
+//
+// `struct:field:omitempty`: disables the omitempty option of the json and xml tags generated for
+// the attribute when set to "false" so that the empty value of the field is always serialized.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:omitempty", "false")
+//
+// `struct:example`: sets the example value of the attribute, the value is converted to the
+// attribute type. An example given with Example takes precedence.
+// Applicable to attributes of primitive types only.
+//
+//        Metadata("struct:example", "42")
+//
+// `struct:binary`: marks the type as serialized in binary form (e.g. with gob) so that goagen
+// checks that all its attributes produce exported struct fields.
+// Applicable to user types and media types.
+//
+//        Metadata("struct:binary")
+//
+// `struct:event:name`: overrides the name of the event type generated for the type in the event
+// registry, the name of the generated Go type is used by default.
+// Applicable to user types and media types.
+//
+//        Metadata("struct:event:name", "bottle.created")
+//
+// `metadata`: includes the header or parameter in the request metadata struct generated for the
+// action.
+// Applicable to action headers and parameters of primitive types only.
+//
+//        Metadata("metadata")
+//
 // `swagger:tag:xxx`: sets the Swagger object field tag xxx.
 // Applicable to resources and actions.
 //
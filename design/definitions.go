@@ -1,11 +1,16 @@
 package design
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/dimfeld/httppath"
 	"github.com/goadesign/goa/dslengine"
@@ -300,6 +305,9 @@ type (
 		// NonZeroAttributes lists the names of the child attributes that cannot have a
 		// zero value (and thus whose presence does not need to be validated).
 		NonZeroAttributes map[string]bool
+		// Embedded lists the object user types named by the "struct:embed" metadata, they
+		// are resolved when the attribute is validated.
+		Embedded []*UserTypeDefinition
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
 		// isCustomExample keeps track of whether the example is given by the user, or
@@ -951,6 +959,8 @@ func (a *AttributeDefinition) IsNonZero(attName string) bool {
 
 // IsPrimitivePointer returns true if the field generated for the given attribute should be a
 // pointer to a primitive type. The target attribute must be an object.
+// BigInt and RawJSON fields are always generated as *big.Int and json.RawMessage whose nil values
+// stand for unset and thus never need an additional pointer.
 func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if !a.Type.IsObject() {
 		panic("checking pointer field on non-object") // bug
@@ -959,14 +969,22 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
-	if att.Type.IsPrimitive() {
+	if att.Type.IsPrimitive() && att.Type.Kind() != BigIntKind && att.Type.Kind() != RawJSONKind {
 		return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
 	}
 	return false
 }
 
 // GenerateExample returns a random instance of the attribute that validates.
+// The example given with the Example DSL or with the "struct:example" metadata is returned
+// instead if there is one.
 func (a *AttributeDefinition) GenerateExample(r *RandomGenerator) interface{} {
+	if a.Example != nil && a.isCustomExample {
+		return a.Example
+	}
+	if example, ok, err := a.metadataExample(); ok && err == nil {
+		return example
+	}
 	if example := newExampleGenerator(a, r).generate(); example != nil {
 		return example
 	}
@@ -989,6 +1007,142 @@ func (a *AttributeDefinition) SetExample(example interface{}) bool {
 	return false
 }
 
+// metadataExample returns the example given with the "struct:example" metadata converted to the
+// attribute type. The boolean is false if there is no such metadata. metadataExample returns an
+// error if the value cannot be converted or is not compatible with the attribute type, only
+// primitive types are supported.
+func (a *AttributeDefinition) metadataExample() (interface{}, bool, error) {
+	vals, ok := a.Metadata["struct:example"]
+	if !ok || len(vals) == 0 {
+		return nil, false, nil
+	}
+	val := vals[0]
+	var (
+		example interface{}
+		err     error
+	)
+	kind := a.Type.Kind()
+	if ut, ok := a.Type.(*UserTypeDefinition); ok {
+		kind = ut.Type.Kind()
+	}
+	switch kind {
+	case BooleanKind:
+		example, err = strconv.ParseBool(val)
+	case IntegerKind:
+		example, err = strconv.Atoi(val)
+	case NumberKind:
+		example, err = strconv.ParseFloat(val, 64)
+	case BigIntKind:
+		if i, ok := new(big.Int).SetString(val, 10); ok {
+			example = i
+		} else {
+			err = fmt.Errorf("invalid integer %#v", val)
+		}
+	case DurationKind:
+		if _, err = time.ParseDuration(val); err == nil {
+			example = val
+		}
+	case StringKind, DateTimeKind, UUIDKind, AnyKind:
+		example = val
+	case RawJSONKind:
+		if !json.Valid([]byte(val)) {
+			err = fmt.Errorf("invalid JSON %#v", val)
+		}
+		example = json.RawMessage(val)
+	default:
+		return nil, true, fmt.Errorf("struct:example metadata is not supported on attributes of type %s", a.Type.Name())
+	}
+	if err != nil || !a.Type.IsCompatible(example) {
+		return nil, true, fmt.Errorf("struct:example value %#v is incompatible with attribute of type %s", val, a.Type.Name())
+	}
+	return example, true, nil
+}
+
+// resolveEmbedded sets Embedded to the user types named by the "struct:embed" metadata. The
+// structs generated for the attribute embed these types and omit the attributes that they define.
+// resolveEmbedded returns an error if a name does not refer to an object user type, if the type
+// embeds the attribute type recursively or if an attribute would produce a struct field that
+// collides with the fields of an embedded type.
+func (a *AttributeDefinition) resolveEmbedded() error {
+	names, ok := a.Metadata["struct:embed"]
+	if !ok || len(names) == 0 {
+		return nil
+	}
+	o := a.Type.ToObject()
+	if o == nil {
+		return fmt.Errorf("struct:embed metadata is not supported on attributes of type %s", a.Type.Name())
+	}
+	embedded := make([]*UserTypeDefinition, len(names))
+	promoted := make(map[string]*AttributeDefinition)
+	taken := make(map[string]string)
+	for i, n := range names {
+		var ut *UserTypeDefinition
+		if Design != nil {
+			ut = Design.Types[n]
+		}
+		if ut == nil || !ut.IsObject() {
+			return fmt.Errorf("struct:embed metadata refers to %#v which is not an object user type", n)
+		}
+		if embedsRecursively(a, ut, make(map[string]bool)) {
+			return fmt.Errorf("struct:embed metadata refers to %#v which embeds the type recursively, use an attribute instead", n)
+		}
+		embedded[i] = ut
+		taken[fieldKey(n, nil)] = fmt.Sprintf("embedded type %s", n)
+		for an, att := range ut.Type.ToObject() {
+			promoted[an] = att
+			taken[fieldKey(an, att)] = fmt.Sprintf("attribute %s of embedded type %s", an, n)
+		}
+	}
+	for n, att := range o {
+		if base, ok := promoted[n]; ok {
+			if base.Type.Kind() != att.Type.Kind() {
+				return fmt.Errorf("attribute %#v conflicts with the attribute of the same name of an embedded type", n)
+			}
+			continue
+		}
+		if other, ok := taken[fieldKey(n, att)]; ok {
+			return fmt.Errorf("the struct field of attribute %#v collides with the field of %s", n, other)
+		}
+	}
+	a.Embedded = embedded
+	return nil
+}
+
+// embedsRecursively returns true if ut is the type defined by att or embeds it directly or through
+// the types it embeds. Embedded types are values so that such types would have an infinite size.
+func embedsRecursively(att *AttributeDefinition, ut *UserTypeDefinition, seen map[string]bool) bool {
+	if ut.AttributeDefinition == att {
+		return true
+	}
+	if seen[ut.TypeName] {
+		return false
+	}
+	seen[ut.TypeName] = true
+	for _, n := range ut.Metadata["struct:embed"] {
+		if embedded := Design.Types[n]; embedded != nil && embedsRecursively(att, embedded, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldKey returns the name of the struct field generated for the attribute with the given name
+// lowercased and without separators, the "struct:field:name" metadata overrides the name if att
+// is not nil. Attributes whose generated fields have the same name have the same key.
+func fieldKey(name string, att *AttributeDefinition) string {
+	if att != nil {
+		if fname, ok := att.Metadata["struct:field:name"]; ok && len(fname) > 0 {
+			name = fname[0]
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
 // finalizeExample goes through each Example and consolidates all of the information it knows i.e.
 // a custom example or auto-generate for the user. It also tracks whether we've randomized
 // the entire example; if so, we shall re-generate the random value for Array/Hash.
@@ -996,6 +1150,10 @@ func (a *AttributeDefinition) finalizeExample(stack []*AttributeDefinition) (int
 	if a.Example != nil || a.isCustomExample {
 		return a.Example, a.isCustomExample
 	}
+	if example, ok, err := a.metadataExample(); ok && err == nil {
+		a.Example, a.isCustomExample = example, true
+		return a.Example, a.isCustomExample
+	}
 
 	// note: must traverse each node to finalize the examples unless given
 	switch true {
@@ -68,3 +68,52 @@ var _ = Describe("IterateHeaders", func() {
 		Ω(names).Should(ConsistOf("a"))
 	})
 })
+
+var _ = Describe("GenerateExample", func() {
+	var att *design.AttributeDefinition
+	var example interface{}
+
+	BeforeEach(func() {
+		att = &design.AttributeDefinition{Type: design.Integer}
+	})
+
+	JustBeforeEach(func() {
+		example = att.GenerateExample(design.NewRandomGenerator("foo"))
+	})
+
+	Context("given a declared example", func() {
+		BeforeEach(func() {
+			Ω(att.SetExample(42)).Should(BeTrue())
+		})
+
+		It("returns the declared example", func() {
+			Ω(example).Should(Equal(42))
+		})
+	})
+
+	Context("given an example defined with metadata", func() {
+		BeforeEach(func() {
+			att.Metadata = dslengine.MetadataDefinition{"struct:example": {"42"}}
+		})
+
+		It("returns the example converted to the attribute type", func() {
+			Ω(example).Should(Equal(42))
+		})
+	})
+
+	Context("given an incompatible example defined with metadata", func() {
+		BeforeEach(func() {
+			att.Metadata = dslengine.MetadataDefinition{"struct:example": {"foo"}}
+		})
+
+		It("generates an example", func() {
+			Ω(example).Should(BeAssignableToTypeOf(0))
+		})
+
+		It("fails validation", func() {
+			err := att.Validate("", att)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`struct:example value "foo" is incompatible`))
+		})
+	})
+})
@@ -1,13 +1,20 @@
 package design
 
-import "github.com/goadesign/goa/dslengine"
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/goadesign/goa/dslengine"
+)
 
 // Dup creates a copy the given data type.
 func Dup(d DataType) DataType {
 	return newDupper().DupType(d)
 }
 
-// DupAtt creates a copy of the given attribute.
+// DupAtt creates a deep copy of the given attribute so that the copy may be modified without
+// affecting the original, e.g. to derive variants of a type. User types referenced multiple
+// times (including recursively) are only copied once so that cycles are preserved.
 func DupAtt(att *AttributeDefinition) *AttributeDefinition {
 	return newDupper().DupAttribute(att)
 }
@@ -44,15 +51,33 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 	if att.Type != nil {
 		dupType = d.DupType(att.Type)
 	}
+	var metaDup dslengine.MetadataDefinition
+	if att.Metadata != nil {
+		metaDup = make(dslengine.MetadataDefinition, len(att.Metadata))
+		for k, v := range att.Metadata {
+			metaDup[k] = append([]string(nil), v...)
+		}
+	}
+	var nzDup map[string]bool
+	if att.NonZeroAttributes != nil {
+		nzDup = make(map[string]bool, len(att.NonZeroAttributes))
+		for n, nz := range att.NonZeroAttributes {
+			nzDup[n] = nz
+		}
+	}
 	dup := AttributeDefinition{
 		Type:              dupType,
+		Reference:         att.Reference,
 		Description:       att.Description,
 		Validation:        valDup,
-		Metadata:          att.Metadata,
-		DefaultValue:      att.DefaultValue,
-		NonZeroAttributes: att.NonZeroAttributes,
+		Metadata:          metaDup,
+		DefaultValue:      dupValue(att.DefaultValue),
+		Example:           dupValue(att.Example),
+		NonZeroAttributes: nzDup,
+		Embedded:          att.Embedded,
 		View:              att.View,
 		DSLFunc:           att.DSLFunc,
+		isCustomExample:   att.isCustomExample,
 	}
 	return &dup
 }
@@ -101,3 +126,49 @@ func (d *dupper) DupType(t DataType) DataType {
 	}
 	panic("unknown type " + t.Name())
 }
+
+// dupValue returns a deep copy of the given default or example value: the slices, maps and big
+// integers that make up the value are copied recursively.
+func dupValue(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	return dupReflectValue(reflect.ValueOf(val)).Interface()
+}
+
+// dupReflectValue implements dupValue.
+func dupReflectValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(dupReflectValue(v.Elem()))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(dupReflectValue(v.Index(i)))
+		}
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res.SetMapIndex(iter.Key(), dupReflectValue(iter.Value()))
+		}
+		return res
+	case reflect.Ptr:
+		if i, ok := v.Interface().(*big.Int); ok && i != nil {
+			return reflect.ValueOf(new(big.Int).Set(i))
+		}
+	}
+	return v
+}
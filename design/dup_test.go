@@ -2,6 +2,7 @@ package design_test
 
 import (
 	. "github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -145,3 +146,49 @@ var _ = Describe("Dup", func() {
 		})
 	})
 })
+
+var _ = Describe("DupAtt", func() {
+	var att *AttributeDefinition
+	var dup *AttributeDefinition
+
+	BeforeEach(func() {
+		att = &AttributeDefinition{
+			Type: Object{
+				"foo": &AttributeDefinition{
+					Type:     String,
+					Metadata: dslengine.MetadataDefinition{"struct:tag:json": []string{"foo"}},
+					Example:  "bar",
+				},
+			},
+			Validation:        &dslengine.ValidationDefinition{Required: []string{"foo"}},
+			NonZeroAttributes: map[string]bool{"foo": true},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dup = DupAtt(att)
+	})
+
+	It("returns a deep copy", func() {
+		Ω(dup).Should(Equal(att))
+		dup.Validation.Required = nil
+		dup.NonZeroAttributes["foo"] = false
+		foo := dup.Type.ToObject()["foo"]
+		foo.Metadata["struct:tag:json"][0] = "baz"
+		Ω(att.Validation.Required).Should(Equal([]string{"foo"}))
+		Ω(att.NonZeroAttributes["foo"]).Should(BeTrue())
+		Ω(att.Type.ToObject()["foo"].Metadata["struct:tag:json"]).Should(Equal([]string{"foo"}))
+	})
+
+	It("copies the examples and default values", func() {
+		att.Example = map[string]interface{}{"foo": []interface{}{"bar"}}
+		att.DefaultValue = map[string]interface{}{"foo": []string{"bar"}}
+		dup = DupAtt(att)
+		Ω(dup.Example).Should(Equal(att.Example))
+		Ω(dup.DefaultValue).Should(Equal(att.DefaultValue))
+		dup.Example.(map[string]interface{})["foo"].([]interface{})[0] = "baz"
+		dup.DefaultValue.(map[string]interface{})["foo"].([]string)[0] = "baz"
+		Ω(att.Example).Should(Equal(map[string]interface{}{"foo": []interface{}{"bar"}}))
+		Ω(att.DefaultValue).Should(Equal(map[string]interface{}{"foo": []string{"bar"}}))
+	})
+})
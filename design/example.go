@@ -225,6 +225,15 @@ func (eg *exampleGenerator) checkMinMaxValueValidation(example interface{}) bool
 }
 
 func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
+	example := eg.generateMinMaxValue()
+	if v, ok := example.(int); ok && eg.a.Type.Kind() == DurationKind {
+		// duration ranges are expressed in nanoseconds
+		return time.Duration(v).String()
+	}
+	return example
+}
+
+func (eg *exampleGenerator) generateMinMaxValue() interface{} {
 	if !eg.hasMinMaxValidation() {
 		return nil
 	}
@@ -235,8 +244,10 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 	if eg.a.Validation.Maximum != nil {
 		max = *eg.a.Validation.Maximum
 	}
+	kind := eg.a.Type.Kind()
+	integer := kind == IntegerKind || kind == BigIntKind || kind == DurationKind
 	if math.IsInf(min, 1) {
-		if eg.a.Type.Kind() == IntegerKind {
+		if integer {
 			if max == 0 {
 				return int(max) - eg.r.Int()%3
 			}
@@ -244,7 +255,7 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 		}
 		return eg.r.Float64() * max
 	} else if math.IsInf(max, -1) {
-		if eg.a.Type.Kind() == IntegerKind {
+		if integer {
 			if min == 0 {
 				return int(min) + eg.r.Int()%3
 			}
@@ -252,12 +263,12 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 		}
 		return min + eg.r.Float64()*min
 	} else if min < max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if integer {
 			return int(min) + eg.r.Int()%int(max-min)
 		}
 		return min + eg.r.Float64()*(max-min)
 	} else if min == max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if integer {
 			return int(min)
 		}
 		return min
@@ -3,6 +3,7 @@ package design
 import (
 	"crypto/md5"
 	"encoding/binary"
+	"math/big"
 	"math/rand"
 	"time"
 
@@ -43,6 +44,16 @@ func (r *RandomGenerator) Int() int {
 	return r.rand.Int()
 }
 
+// BigInt produces a random big integer.
+func (r *RandomGenerator) BigInt() *big.Int {
+	return big.NewInt(r.rand.Int63())
+}
+
+// Duration produces a random duration formatted as a string accepted by time.ParseDuration.
+func (r *RandomGenerator) Duration() string {
+	return (time.Duration(r.rand.Int63n(int64(24*time.Hour))) / time.Second * time.Second).String()
+}
+
 // String produces a random string.
 func (r *RandomGenerator) String() string {
 	return r.faker.Sentence(2, false)
@@ -11,6 +11,7 @@ package design
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"sort"
 	"strings"
@@ -146,6 +147,13 @@ const (
 	UserTypeKind
 	// MediaTypeKind represents a media type.
 	MediaTypeKind
+	// BigIntKind represents a JSON integer that is parsed as a Go *big.Int.
+	BigIntKind
+	// DurationKind represents a JSON string that is parsed as a Go goa.Duration.
+	DurationKind
+	// RawJSONKind represents any JSON value whose decoding is deferred, it is kept as a Go
+	// json.RawMessage.
+	RawJSONKind
 )
 
 const (
@@ -171,6 +179,17 @@ const (
 
 	// Any is the type for an arbitrary JSON value (interface{} in Go).
 	Any = Primitive(AnyKind)
+
+	// BigInt is the type for a JSON integer of arbitrary precision parsed as a Go *big.Int.
+	BigInt = Primitive(BigIntKind)
+
+	// Duration is the type for a JSON string parsed as a Go goa.Duration which converts to
+	// time.Duration. Duration expects a value accepted by time.ParseDuration such as "1h30m".
+	Duration = Primitive(DurationKind)
+
+	// RawJSON is the type for any JSON value kept undecoded as a Go json.RawMessage, e.g. to
+	// pass payloads through. Its values are not validated.
+	RawJSON = Primitive(RawJSONKind)
 )
 
 // DataType implementation
@@ -183,13 +202,13 @@ func (p Primitive) Name() string {
 	switch p {
 	case Boolean:
 		return "boolean"
-	case Integer:
+	case Integer, BigInt:
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Duration:
 		return "string"
-	case Any:
+	case Any, RawJSON:
 		return "any"
 	default:
 		panic("unknown primitive type") // bug
@@ -231,19 +250,23 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any && p != BigInt && p != Duration && p != RawJSON {
 		panic("unknown primitive type") // bug
 	}
-	if p == Any {
+	if p == Any || p == RawJSON {
 		return true
 	}
 	switch val.(type) {
 	case bool:
 		return p == Boolean
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return p == Integer || p == Number
+		return p == Integer || p == Number || p == BigInt
 	case float32, float64:
 		return p == Number
+	case *big.Int:
+		return p == BigInt
+	case time.Duration:
+		return p == Duration
 	case string:
 		if p == String {
 			return true
@@ -256,6 +279,14 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == BigInt {
+			_, ok := new(big.Int).SetString(val.(string), 10)
+			return ok
+		}
+		if p == Duration {
+			_, err := time.ParseDuration(val.(string))
+			return err == nil
+		}
 	}
 	return false
 }
@@ -277,7 +308,11 @@ func (p Primitive) GenerateExample(r *RandomGenerator) interface{} {
 		return r.DateTime()
 	case UUID:
 		return r.UUID()
-	case Any:
+	case BigInt:
+		return r.BigInt()
+	case Duration:
+		return r.Duration()
+	case Any, RawJSON:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r)
 	default:
@@ -1,7 +1,10 @@
 package design_test
 
 import (
+	"encoding/json"
 	"errors"
+	"math/big"
+	"time"
 
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -271,3 +274,56 @@ var _ = Describe("MediaTypeDefinition", func() {
 		})
 	})
 })
+
+var _ = Describe("BigInt", func() {
+	It("is compatible with integers and big integers", func() {
+		Expect(BigInt.IsCompatible(42)).To(BeTrue())
+		Expect(BigInt.IsCompatible(big.NewInt(42))).To(BeTrue())
+		Expect(BigInt.IsCompatible("123456789012345678901234567890")).To(BeTrue())
+	})
+
+	It("is not compatible with other values", func() {
+		Expect(BigInt.IsCompatible(4.2)).To(BeFalse())
+		Expect(BigInt.IsCompatible("foo")).To(BeFalse())
+		Expect(Integer.IsCompatible(big.NewInt(42))).To(BeFalse())
+	})
+
+	It("generates big integer examples", func() {
+		Expect(BigInt.GenerateExample(NewRandomGenerator("foo"))).To(BeAssignableToTypeOf(&big.Int{}))
+	})
+})
+
+var _ = Describe("Duration", func() {
+	It("is compatible with durations and duration strings", func() {
+		Expect(Duration.IsCompatible(time.Second)).To(BeTrue())
+		Expect(Duration.IsCompatible("1h30m")).To(BeTrue())
+	})
+
+	It("is not compatible with other values", func() {
+		Expect(Duration.IsCompatible(42)).To(BeFalse())
+		Expect(Duration.IsCompatible("forever")).To(BeFalse())
+	})
+
+	It("generates duration examples", func() {
+		example := Duration.GenerateExample(NewRandomGenerator("foo"))
+		Expect(example).To(BeAssignableToTypeOf(""))
+		_, err := time.ParseDuration(example.(string))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("RawJSON", func() {
+	It("is compatible with any value", func() {
+		Expect(RawJSON.IsCompatible(json.RawMessage(`{"a":1}`))).To(BeTrue())
+		Expect(RawJSON.IsCompatible(42)).To(BeTrue())
+	})
+
+	It("has the JSON type name of any value", func() {
+		Expect(RawJSON.Name()).To(Equal("any"))
+	})
+
+	It("is never generated as a pointer", func() {
+		att := &AttributeDefinition{Type: Object{"payload": &AttributeDefinition{Type: RawJSON}}}
+		Expect(att.IsPrimitivePointer("payload")).To(BeFalse())
+	})
+})
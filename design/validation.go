@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/goadesign/goa/dslengine"
@@ -413,6 +414,7 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 		verr.Add(parent, "attribute type is nil")
 		return verr
 	}
+	label := ctx
 	if ctx != "" {
 		ctx += " - "
 	}
@@ -431,6 +433,38 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
 		}
 	}
+	if _, ok, err := a.metadataExample(); ok && err != nil {
+		verr.Add(parent, "%s%s", ctx, err)
+	}
+	if err := a.resolveEmbedded(); err != nil {
+		verr.Add(parent, "%s%s", ctx, err)
+	}
+	if display, ok := a.Metadata["struct:display"]; ok && len(display) > 0 {
+		if o := a.Type.ToObject(); o == nil {
+			verr.Add(parent, "%sstruct:display metadata is only supported on object attributes", ctx)
+		} else if _, ok := o[display[0]]; !ok {
+			verr.Add(parent, "%sstruct:display metadata refers to unknown attribute %#v", ctx, display[0])
+		}
+	}
+	if kinds, ok := a.Metadata["struct:flexible"]; ok && len(kinds) > 0 {
+		if !a.Type.IsPrimitive() {
+			verr.Add(parent, "%sstruct:flexible metadata is only supported on primitive attributes", ctx)
+		}
+		for _, k := range kinds {
+			switch k {
+			case "boolean", "integer", "number", "string":
+			default:
+				verr.Add(parent, "%sinvalid struct:flexible kind %#v, must be one of boolean, integer, number or string", ctx, k)
+			}
+		}
+	}
+	if hint, ok := a.Metadata["struct:field:capacity"]; ok && len(hint) > 0 {
+		if !a.Type.IsArray() {
+			verr.Add(parent, "%sstruct:field:capacity metadata is only supported on array attributes", ctx)
+		} else if c, err := strconv.Atoi(hint[0]); err != nil || c < 0 {
+			verr.Add(parent, "%sinvalid struct:field:capacity metadata %#v, must be a non-negative integer", ctx, hint[0])
+		}
+	}
 	o := a.Type.ToObject()
 	if o != nil {
 		for _, n := range a.AllRequired() {
@@ -449,16 +483,71 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
 		}
-	} else {
-		if a.Type.IsArray() {
-			elemType := a.Type.ToArray().ElemType
-			verr.Merge(elemType.Validate(ctx, a))
-		}
+	} else if a.Type.IsArray() {
+		elemType := a.Type.ToArray().ElemType
+		verr.Merge(elemType.Validate(ctx, a))
+	} else if h := a.Type.ToHash(); h != nil {
+		verr.Merge(h.Validate(label, parent))
 	}
 
 	return verr.AsError()
 }
 
+// Validate checks that the hash key type is a comparable primitive type: arrays, hashes and
+// objects cannot be used as keys as they are not comparable, neither can Any whose values may not
+// be comparable, RawJSON whose values are slices and BigInt whose values are pointers that would
+// be compared by address. It also validates the key and element attributes. ctx describes the
+// hash attribute in error messages, e.g. "field tags", and may be empty.
+func (h *Hash) Validate(ctx string, parent dslengine.Definition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	prefix := ctx
+	if prefix != "" {
+		prefix += " - "
+	}
+	if h.KeyType == nil || h.ElemType == nil {
+		verr.Add(parent, "%shash key and element types must be defined", prefix)
+		return verr
+	}
+	if key := h.KeyType.Type; key != nil {
+		switch underlyingKind(key) {
+		case ArrayKind, HashKind, ObjectKind:
+			verr.Add(parent, "%sinvalid hash key type %s, hash keys must be primitive values", prefix, key.Name())
+		case AnyKind, RawJSONKind, BigIntKind:
+			verr.Add(parent, "%sinvalid hash key type %s, hash keys must be comparable primitive values", prefix, hashKeyName(key))
+		}
+	}
+	verr.Merge(h.KeyType.Validate(prefix+"hash key", parent))
+	verr.Merge(h.ElemType.Validate(prefix+"hash element", parent))
+	return verr.AsError()
+}
+
+// underlyingKind returns the kind of the type of the values of dt, that is the kind of the type
+// of the attribute of user types and media types.
+func underlyingKind(dt DataType) Kind {
+	for {
+		switch actual := dt.(type) {
+		case *MediaTypeDefinition:
+			dt = actual.Type
+		case *UserTypeDefinition:
+			dt = actual.Type
+		default:
+			return dt.Kind()
+		}
+	}
+}
+
+// hashKeyName returns the name of the primitive hash key type used in error messages. Name
+// returns the JSON type name which does not distinguish RawJSON from Any or BigInt from Integer.
+func hashKeyName(dt DataType) string {
+	switch underlyingKind(dt) {
+	case RawJSONKind:
+		return "RawJSON"
+	case BigIntKind:
+		return "BigInt"
+	}
+	return dt.Name()
+}
+
 // Validate checks that the response definition is consistent: its status is set and the media
 // type definition if any is valid.
 func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
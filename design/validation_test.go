@@ -78,6 +78,114 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with hash attributes", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, HashOf(String, Integer))
+					Attribute("byList", HashOf(ArrayOf(String), Integer))
+					Attribute("byHash", HashOf(HashOf(String, String), Integer))
+				}
+			})
+
+			It("reports all the invalid key types", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field byList - invalid hash key type array"))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field byHash - invalid hash key type hash"))
+			})
+		})
+
+		Context("with hash attributes whose keys are not comparable", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("byAny", HashOf(Any, Integer))
+					Attribute("byRaw", HashOf(RawJSON, Integer))
+					Attribute("byBig", HashOf(BigInt, Integer))
+				}
+			})
+
+			It("reports the Any, RawJSON and BigInt keys", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field byAny - invalid hash key type any, hash keys must be comparable primitive values"))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field byRaw - invalid hash key type RawJSON, hash keys must be comparable primitive values"))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field byBig - invalid hash key type BigInt, hash keys must be comparable primitive values"))
+			})
+		})
+
+		Context("with a display attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("struct:display", attName)
+					Attribute(attName, String)
+				}
+			})
+
+			It("accepts the attribute", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an unknown display attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("struct:display", "foo")
+					Attribute(attName, String)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`struct:display metadata refers to unknown attribute "foo"`))
+			})
+		})
+
+		Context("with flexible attributes", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Any, func() {
+						Metadata("struct:flexible", "integer", "string")
+					})
+					Attribute("date", Any, func() {
+						Metadata("struct:flexible", "integer", "date")
+					})
+				}
+			})
+
+			It("reports the unknown kinds", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				msg := dslengine.Errors.Error()
+				Ω(msg).ShouldNot(ContainSubstring("field " + attName))
+				Ω(msg).Should(ContainSubstring(`field date - invalid struct:flexible kind "date", must be one of boolean, integer, number or string`))
+			})
+		})
+
+		Context("with a capacity hint", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, ArrayOf(String), func() {
+						Metadata("struct:field:capacity", "16")
+					})
+					Attribute("negative", ArrayOf(String), func() {
+						Metadata("struct:field:capacity", "-1")
+					})
+					Attribute("many", ArrayOf(String), func() {
+						Metadata("struct:field:capacity", "many")
+					})
+					Attribute("scalar", String, func() {
+						Metadata("struct:field:capacity", "16")
+					})
+				}
+			})
+
+			It("reports the invalid hints", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				msg := dslengine.Errors.Error()
+				Ω(msg).ShouldNot(ContainSubstring("field " + attName))
+				Ω(msg).Should(ContainSubstring(`field negative - invalid struct:field:capacity metadata "-1", must be a non-negative integer`))
+				Ω(msg).Should(ContainSubstring(`field many - invalid struct:field:capacity metadata "many", must be a non-negative integer`))
+				Ω(msg).Should(ContainSubstring("field scalar - struct:field:capacity metadata is only supported on array attributes"))
+			})
+		})
+
 		Context("with a valid format validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -287,4 +395,85 @@ var _ = Describe("Validation", func() {
 			})
 		})
 	})
+
+	Context("with embedded types", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Type("Audit", func() {
+				Attribute("created_at", DateTime)
+			})
+			Type("bar", func() {
+				Metadata("struct:embed", "Audit")
+				Attribute("created_at", DateTime)
+				Attribute("name", String)
+				dsl()
+			})
+			dslengine.Run()
+		})
+
+		BeforeEach(func() {
+			dsl = func() {}
+		})
+
+		It("resolves the embedded types", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Types["bar"].Embedded).Should(Equal([]*UserTypeDefinition{Design.Types["Audit"]}))
+		})
+
+		Context("with an attribute whose field collides with an embedded field", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("createdAt", String)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					`the struct field of attribute "createdAt" collides with the field of attribute created_at of embedded type Audit`))
+			})
+		})
+
+		Context("with an attribute that conflicts with an embedded attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("created_at", String)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					`attribute "created_at" conflicts with the attribute of the same name of an embedded type`))
+			})
+		})
+
+		Context("with an unknown type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("struct:embed", "Unknown")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"Unknown" which is not an object user type`))
+			})
+		})
+
+		Context("with a type that embeds itself", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("struct:embed", "bar")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("embeds the type recursively"))
+			})
+		})
+	})
 })
@@ -100,6 +100,9 @@ type (
 		// MaxLength represents an maximum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor26.
 		MaxLength *int
+		// UniqueItems represents a unique items validation of array attributes as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor49.
+		UniqueItems bool
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
@@ -147,6 +150,7 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	v.UniqueItems = v.UniqueItems || other.UniqueItems
 	v.AddRequired(other.Required)
 }
 
@@ -169,13 +173,14 @@ func (v *ValidationDefinition) AddRequired(required []string) {
 // Dup makes a shallow dup of the validation.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:      v.Values,
+		Format:      v.Format,
+		Pattern:     v.Pattern,
+		Minimum:     v.Minimum,
+		Maximum:     v.Maximum,
+		MinLength:   v.MinLength,
+		MaxLength:   v.MaxLength,
+		UniqueItems: v.UniqueItems,
+		Required:    v.Required,
 	}
 }
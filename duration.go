@@ -0,0 +1,55 @@
+package goa
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is the type of the fields generated for the attributes of type design.Duration. Its
+// values are encoded as strings accepted by time.ParseDuration, e.g. "1h30m", rather than as the
+// number of nanoseconds time.Duration uses. Fields of type time.Duration would be encoded as
+// integers by encoding/json, which contradicts the string type of the JSON schema and of the
+// documentation, and every generated marshaler would need to handle them specially. Converting
+// a Duration to time.Duration is free: time.Duration(d).
+type Duration time.Duration
+
+// ParseDuration parses s with time.ParseDuration.
+func ParseDuration(s string) (Duration, error) {
+	d, err := time.ParseDuration(s)
+	return Duration(d), err
+}
+
+// String returns the duration formatted like time.Duration, e.g. "1h30m0s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON encodes the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a JSON string accepted by time.ParseDuration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a JSON string, got %s", data)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,41 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Duration", func() {
+	// payload has the fields generated for optional and required Duration attributes.
+	type payload struct {
+		Timeout  *goa.Duration `json:"timeout,omitempty"`
+		Interval goa.Duration  `json:"interval"`
+	}
+
+	It("round-trips JSON strings", func() {
+		var p payload
+		Ω(json.Unmarshal([]byte(`{"timeout":"1h30m","interval":"250ms"}`), &p)).Should(Succeed())
+		Ω(p.Timeout).ShouldNot(BeNil())
+		Ω(time.Duration(*p.Timeout)).Should(Equal(90 * time.Minute))
+		Ω(time.Duration(p.Interval)).Should(Equal(250 * time.Millisecond))
+		b, err := json.Marshal(p)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"timeout":"1h30m0s","interval":"250ms"}`))
+	})
+
+	It("rejects numbers and invalid durations", func() {
+		var p payload
+		Ω(json.Unmarshal([]byte(`{"interval":1000}`), &p)).ShouldNot(Succeed())
+		Ω(json.Unmarshal([]byte(`{"interval":"forever"}`), &p)).ShouldNot(Succeed())
+	})
+
+	It("parses durations", func() {
+		d, err := goa.ParseDuration("2s")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(goa.Duration(2 * time.Second)))
+	})
+})
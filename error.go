@@ -169,6 +169,13 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest("length of %s must be %s than %d but got value %#v (len=%d)", ctx, comp, value, target, ln)
 }
 
+// InvalidUniqueItemsError is the error produced when the value of a parameter or payload field
+// contains the same item more than once in violation of the unique items validation defined in
+// the design.
+func InvalidUniqueItemsError(ctx string, item interface{}) *Error {
+	return ErrInvalidRequest("items of %s must be unique but got value %#v more than once", ctx, item)
+}
+
 // NoSecurityScheme is the error produced when goa is unable to lookup a security scheme defined in
 // the design.
 func NoSecurityScheme(schemeName string) *Error {
@@ -219,6 +219,25 @@ var _ = Describe("InvalidRangeError", func() {
 	})
 })
 
+var _ = Describe("InvalidUniqueItemsError", func() {
+	var valErr error
+	ctx := "ctx"
+	item := "item"
+
+	JustBeforeEach(func() {
+		valErr = goa.InvalidUniqueItemsError(ctx, item)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&goa.Error{}))
+		err := valErr.(*goa.Error)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("unique"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", item)))
+	})
+})
+
 var _ = Describe("InvalidLengthError", func() {
 	const ctx = "ctx"
 	const value = 42
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var bitmapAccessorsT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if bitmapAccessorsT, err = template.New("bitmapAccessors").Parse(bitmapAccessorsTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// bitmapField describes a field whose presence is recorded in the bitmap.
+type bitmapField struct {
+	// Name is the name of the struct field.
+	Name string
+	// Type is the Go type of the field.
+	Type string
+	// Bit is the index of the bit recording whether the field is set.
+	Bit int
+}
+
+// GoBitmapAccessors produces the Go code of the methods that test and set the optional primitive
+// fields of the struct generated for the given object user type with the BitmapFields mode, e.g.
+// "HasRating" and "SetRating". The function returns the empty string if the type has no optional
+// primitive field.
+func GoBitmapAccessors(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: bitmap accessors require an object user type")
+	}
+	att := ut.AttributeDefinition
+	names := bitmapFields(att, false)
+	if len(names) == 0 {
+		return ""
+	}
+	o := att.Type.ToObject()
+	fields := make([]*bitmapField, len(names))
+	for i, n := range names {
+		field := o[n]
+		fields[i] = &bitmapField{
+			Name: goFieldName(n, field),
+			Type: GoTypeName(field.Type, field.AllRequired(), 0, false),
+			Bit:  i,
+		}
+	}
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": fields,
+	}
+	return RunTemplate(bitmapAccessorsT, data)
+}
+
+const bitmapAccessorsTmpl = `{{ $name := .Name }}{{ range $i, $f := .Fields }}{{ if $i }}
+{{ end }}// Has{{ $f.Name }} returns true if the {{ $f.Name }} field is set.
+func (ut *{{ $name }}) Has{{ $f.Name }}() bool {
+	return ut.fieldsSet&(1<<{{ $f.Bit }}) != 0
+}
+
+// Set{{ $f.Name }} sets the {{ $f.Name }} field.
+func (ut *{{ $name }}) Set{{ $f.Name }}(v {{ $f.Type }}) {
+	ut.{{ $f.Name }} = v
+	ut.fieldsSet |= 1 << {{ $f.Bit }}
+}
+{{ end }}`
@@ -0,0 +1,71 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bitmap fields", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":    &design.AttributeDefinition{Type: design.String},
+					"rating":  &design.AttributeDefinition{Type: design.Integer},
+					"vintage": &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	It("generates value fields and the bitmap", func() {
+		st := codegen.GoTypeDefMode(ut, 0, false, false, codegen.BitmapFields)
+		Ω(st).Should(Equal("struct {\n\tName string\n\tRating int\n\tVintage int\n\tfieldsSet uint64\n}"))
+	})
+
+	It("generates the accessors", func() {
+		Ω(codegen.GoBitmapAccessors(ut)).Should(Equal(bitmapAccessorsCode))
+	})
+
+	Context("with no optional field", func() {
+		BeforeEach(func() {
+			ut.Validation.Required = []string{"name", "rating", "vintage"}
+		})
+
+		It("generates no bitmap", func() {
+			st := codegen.GoTypeDefMode(ut, 0, false, false, codegen.BitmapFields)
+			Ω(st).Should(Equal("struct {\n\tName string\n\tRating int\n\tVintage int\n}"))
+			Ω(codegen.GoBitmapAccessors(ut)).Should(BeEmpty())
+		})
+	})
+})
+
+const bitmapAccessorsCode = `// HasRating returns true if the Rating field is set.
+func (ut *Bottle) HasRating() bool {
+	return ut.fieldsSet&(1<<0) != 0
+}
+
+// SetRating sets the Rating field.
+func (ut *Bottle) SetRating(v int) {
+	ut.Rating = v
+	ut.fieldsSet |= 1 << 0
+}
+
+// HasVintage returns true if the Vintage field is set.
+func (ut *Bottle) HasVintage() bool {
+	return ut.fieldsSet&(1<<1) != 0
+}
+
+// SetVintage sets the Vintage field.
+func (ut *Bottle) SetVintage(v int) {
+	ut.Vintage = v
+	ut.fieldsSet |= 1 << 1
+}
+`
@@ -0,0 +1,49 @@
+package codegen
+
+import "github.com/goadesign/goa/design"
+
+// TypeNameCache memoizes the Go type names computed by GoTypeName for user types, media types,
+// arrays and hashes keyed on the identity of the data type. A cache must be created with
+// NewTypeNameCache for each generation run: it assumes that the design does not change while the
+// cache is in use and it is not safe for concurrent use. A nil cache is valid and disables the
+// memoization.
+type TypeNameCache struct {
+	names map[typeNameKey]string
+}
+
+// typeNameKey identifies a type name computation. Inline objects are not cached as they cannot be
+// used as map keys, the Go names of arrays and hashes depend on tabs if their elements are inline
+// objects.
+type typeNameKey struct {
+	t       design.DataType
+	tabs    int
+	private bool
+}
+
+// NewTypeNameCache returns an empty type name cache.
+func NewTypeNameCache() *TypeNameCache {
+	return &TypeNameCache{names: make(map[typeNameKey]string)}
+}
+
+// GoTypeName returns the same value as the GoTypeName function, the value is computed only once
+// for a given data type.
+func (c *TypeNameCache) GoTypeName(t design.DataType, required []string, tabs int, private bool) string {
+	if c == nil {
+		return GoTypeName(t, required, tabs, private)
+	}
+	var key typeNameKey
+	switch t.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		key = typeNameKey{t: t, private: private}
+	case *design.Array, *design.Hash:
+		key = typeNameKey{t: t, tabs: tabs, private: private}
+	default:
+		return GoTypeName(t, required, tabs, private)
+	}
+	if name, ok := c.names[key]; ok {
+		return name
+	}
+	name := GoTypeName(t, required, tabs, private)
+	c.names[key] = name
+	return name
+}
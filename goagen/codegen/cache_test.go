@@ -0,0 +1,48 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TypeNameCache", func() {
+	var cache *codegen.TypeNameCache
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		cache = codegen.NewTypeNameCache()
+		ut = &design.UserTypeDefinition{
+			TypeName:            "bottle",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+		}
+	})
+
+	It("returns the same names as GoTypeName", func() {
+		array := &design.Array{ElemType: &design.AttributeDefinition{Type: ut}}
+		Ω(cache.GoTypeName(ut, nil, 0, false)).Should(Equal("Bottle"))
+		Ω(cache.GoTypeName(ut, nil, 0, true)).Should(Equal("bottle"))
+		Ω(cache.GoTypeName(array, nil, 0, false)).Should(Equal("[]Bottle"))
+		Ω(cache.GoTypeName(design.Integer, nil, 0, false)).Should(Equal("int"))
+	})
+
+	It("computes the name of a type only once", func() {
+		Ω(cache.GoTypeName(ut, nil, 0, false)).Should(Equal("Bottle"))
+		ut.TypeName = "account"
+		Ω(cache.GoTypeName(ut, nil, 0, false)).Should(Equal("Bottle"))
+		Ω(codegen.NewTypeNameCache().GoTypeName(ut, nil, 0, false)).Should(Equal("Account"))
+	})
+
+	Context("when nil", func() {
+		BeforeEach(func() {
+			cache = nil
+		})
+
+		It("does not memoize the names", func() {
+			Ω(cache.GoTypeName(ut, nil, 0, false)).Should(Equal("Bottle"))
+			ut.TypeName = "account"
+			Ω(cache.GoTypeName(ut, nil, 0, false)).Should(Equal("Account"))
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var cloneMethodT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if cloneMethodT, err = template.New("cloneMethod").Parse(cloneMethodTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoClone produces the Go code of the Clone method of the given object user type. The method
+// returns a copy of the instance that shares no memory with it: pointers to primitive values are
+// copied, slices and maps are reallocated and their elements cloned at every level and fields
+// whose type is an object user type are cloned by calling their own Clone method. Nil pointers,
+// slices and maps stay nil in the copy. Values of Any fields are not copied.
+// The generated code requires the "math/big" package if the type has big integer fields and the
+// "encoding/json" package if it has raw JSON fields.
+func GoClone(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: Clone method requires an object user type")
+	}
+	var buf bytes.Buffer
+	writeCloneFields(&buf, ut.AttributeDefinition, "ut", "res", 1)
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": buf.String(),
+	}
+	return RunTemplate(cloneMethodT, data)
+}
+
+// writeCloneFields writes the code that clones the fields of src into dst, dst must be a shallow
+// copy of src. att must be an object.
+func writeCloneFields(buf *bytes.Buffer, att *design.AttributeDefinition, src, dst string, depth int) {
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		name := goFieldName(n, catt)
+		sfield, dfield := fmt.Sprintf("%s.%s", src, name), fmt.Sprintf("%s.%s", dst, name)
+		if !catt.Type.IsObject() && att.IsPrimitivePointer(n) {
+			v := fmt.Sprintf("v%d", depth)
+			writeLine(buf, depth, "if %s != nil {", sfield)
+			writeLine(buf, depth+1, "%s := *%s", v, sfield)
+			writeLine(buf, depth+1, "%s = &%s", dfield, v)
+			writeLine(buf, depth, "}")
+			return nil
+		}
+		if isShallowClone(catt) {
+			// copied by the shallow copy
+			return nil
+		}
+		writeCloneValue(buf, catt, sfield, dfield, depth)
+		return nil
+	})
+}
+
+// writeCloneValue writes the code that assigns a clone of src to dst.
+func writeCloneValue(buf *bytes.Buffer, att *design.AttributeDefinition, src, dst string, depth int) {
+	typ := goValueTypeRef(att, depth)
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		if actual.IsObject() {
+			writeLine(buf, depth, "%s = %s.Clone()", dst, src)
+			return
+		}
+		att = actual.AttributeDefinition
+	case *design.MediaTypeDefinition:
+		if actual.IsObject() {
+			writeLine(buf, depth, "%s = %s.Clone()", dst, src)
+			return
+		}
+		att = actual.AttributeDefinition
+	}
+
+	switch att.Type.Kind() {
+	case design.BigIntKind:
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = new(big.Int).Set(%s)", dst, src)
+		writeLine(buf, depth, "}")
+	case design.RawJSONKind:
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = append(json.RawMessage(nil), %s...)", dst, src)
+		writeLine(buf, depth, "}")
+	case design.ArrayKind:
+		i, e := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+		elem := att.Type.ToArray().ElemType
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = make(%s, len(%s))", dst, typ, src)
+		if isShallowClone(elem) {
+			writeLine(buf, depth+1, "copy(%s, %s)", dst, src)
+		} else {
+			writeLine(buf, depth+1, "for %s, %s := range %s {", i, e, src)
+			writeCloneValue(buf, elem, e, fmt.Sprintf("%s[%s]", dst, i), depth+2)
+			writeLine(buf, depth+1, "}")
+		}
+		writeLine(buf, depth, "}")
+	case design.HashKind:
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		elem := att.Type.ToHash().ElemType
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = make(%s, len(%s))", dst, typ, src)
+		writeLine(buf, depth+1, "for %s, %s := range %s {", k, v, src)
+		if isShallowClone(elem) {
+			writeLine(buf, depth+2, "%s[%s] = %s", dst, k, v)
+		} else {
+			// clone into a variable so that nil elements are kept
+			c := fmt.Sprintf("c%d", depth)
+			writeLine(buf, depth+2, "var %s %s", c, goValueTypeRef(elem, depth+2))
+			writeCloneValue(buf, elem, v, c, depth+2)
+			writeLine(buf, depth+2, "%s[%s] = %s", dst, k, c)
+		}
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth, "}")
+	case design.ObjectKind:
+		o := fmt.Sprintf("o%d", depth)
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s := *%s", o, src)
+		writeCloneFields(buf, att, src, o, depth+1)
+		writeLine(buf, depth+1, "%s = &%s", dst, o)
+		writeLine(buf, depth, "}")
+	default:
+		writeLine(buf, depth, "%s = %s", dst, src)
+	}
+}
+
+// isShallowClone returns true if the values of att can be copied with an assignment.
+func isShallowClone(att *design.AttributeDefinition) bool {
+	return att.Type.IsPrimitive() && !isNilablePrimitive(att.Type)
+}
+
+const cloneMethodTmpl = `// Clone returns a copy of the {{ .Name }} instance that shares no memory with it.
+func (ut *{{ .Name }}) Clone() *{{ .Name }} {
+	if ut == nil {
+		return nil
+	}
+	res := *ut
+{{ .Fields }}	return &res
+}
+`
@@ -0,0 +1,92 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoClone", func() {
+	var elem, ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		elem = &design.UserTypeDefinition{
+			TypeName: "elem",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{"id": &design.AttributeDefinition{Type: design.Integer}},
+			},
+		}
+		elems := &design.Array{ElemType: &design.AttributeDefinition{Type: elem}}
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"elem": &design.AttributeDefinition{Type: elem},
+					"groups": &design.AttributeDefinition{Type: &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: elems},
+					}},
+				},
+			},
+		}
+	})
+
+	It("clones all the layers of nested collections", func() {
+		Ω(codegen.GoClone(ut)).Should(Equal(cloneCode))
+	})
+
+	Context("with collections of inline objects", func() {
+		BeforeEach(func() {
+			inline := &design.AttributeDefinition{
+				Type: design.Object{"x": &design.AttributeDefinition{Type: design.Integer}},
+			}
+			ut.Type = design.Object{
+				"items": &design.AttributeDefinition{Type: &design.Array{ElemType: inline}},
+				"index": &design.AttributeDefinition{Type: &design.Hash{
+					KeyType:  &design.AttributeDefinition{Type: design.String},
+					ElemType: inline,
+				}},
+			}
+		})
+
+		It("generates code that compiles", func() {
+			code := "type Bottle " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + codegen.GoClone(ut)
+			Ω(typeCheck(code)).Should(Succeed())
+		})
+	})
+
+	It("rejects non object types", func() {
+		ut.Type = design.String
+		Ω(func() { codegen.GoClone(ut) }).Should(Panic())
+	})
+})
+
+const cloneCode = `// Clone returns a copy of the Bottle instance that shares no memory with it.
+func (ut *Bottle) Clone() *Bottle {
+	if ut == nil {
+		return nil
+	}
+	res := *ut
+	res.Elem = ut.Elem.Clone()
+	if ut.Groups != nil {
+		res.Groups = make(map[string][]*Elem, len(ut.Groups))
+		for k1, v1 := range ut.Groups {
+			var c1 []*Elem
+			if v1 != nil {
+				c1 = make([]*Elem, len(v1))
+				for i3, e3 := range v1 {
+					c1[i3] = e3.Clone()
+				}
+			}
+			res.Groups[k1] = c1
+		}
+	}
+	if ut.Name != nil {
+		v1 := *ut.Name
+		res.Name = &v1
+	}
+	return &res
+}
+`
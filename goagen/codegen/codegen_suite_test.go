@@ -1,6 +1,14 @@
 package codegen_test
 
 import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -11,3 +19,22 @@ func TestCodegen(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Codegen Suite")
 }
+
+// typeCheck type-checks the given generated Go declarations in a package that imports the given
+// standard library packages and returns the first compilation error if any.
+func typeCheck(code string, imports ...string) error {
+	var src strings.Builder
+	src.WriteString("package test\n\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&src, "import %q\n", imp)
+	}
+	src.WriteString(code)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "test.go", src.String(), 0)
+	if err != nil {
+		return err
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("test", fset, []*ast.File{f}, nil)
+	return err
+}
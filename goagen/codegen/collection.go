@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var collectionT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if collectionT, err = template.New("collection").Parse(collectionTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoCollectionType produces the Go code that declares the named slice type of the given array
+// user type, e.g. "type IDs []ID", together with a Len and a Filter method. A Contains method is
+// also generated if the elements can be compared with ==, that is if they are neither objects,
+// which are generated as pointers, nor values of a non comparable type (see IsComparable).
+// methods lists the names of the other methods generated for the type, e.g. "Validate", the names
+// of the collection methods that collide with them are suffixed with a number, e.g. "Len2".
+// The Compact and Deref functions are also generated if the elements are object user types, e.g.
+// `CompactBottles(in []*Bottle) []*Bottle` drops the nil elements and
+// `DerefBottles(in []*Bottle) []Bottle` returns the values of the non nil elements.
+func GoCollectionType(ut *design.UserTypeDefinition, methods ...string) string {
+	if !ut.IsArray() {
+		panic("goa bug: collection types require an array user type")
+	}
+	elem := ut.Type.ToArray().ElemType
+	taken := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		taken[m] = true
+	}
+	data := map[string]interface{}{
+		"Name":     GoTypeName(ut, nil, 0, false),
+		"ElemType": GoTypeRef(elem.Type, elem.AllRequired(), 0, false),
+		"Len":      uniqueName("Len", taken),
+		"Filter":   uniqueName("Filter", taken),
+	}
+	if IsComparable(elem.Type) && !elem.Type.IsObject() {
+		data["Contains"] = uniqueName("Contains", taken)
+	}
+	if isObjectUserType(elem.Type) {
+		data["ElemName"] = GoTypeName(elem.Type, nil, 0, false)
+	}
+	return RunTemplate(collectionT, data)
+}
+
+const collectionTmpl = `// {{ .Name }} is a collection of {{ .ElemType }} values.
+type {{ .Name }} []{{ .ElemType }}
+
+// {{ .Len }} returns the number of elements in the collection.
+func (c {{ .Name }}) {{ .Len }}() int {
+	return len(c)
+}
+{{ if .Contains }}
+// {{ .Contains }} returns true if the collection contains v.
+func (c {{ .Name }}) {{ .Contains }}(v {{ .ElemType }}) bool {
+	for _, e := range c {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+{{ end }}
+// {{ .Filter }} returns the elements of the collection for which f returns true.
+func (c {{ .Name }}) {{ .Filter }}(f func({{ .ElemType }}) bool) {{ .Name }} {
+	var res {{ .Name }}
+	for _, e := range c {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+{{ if .ElemName }}
+// Compact{{ .Name }} returns the non nil elements of in.
+func Compact{{ .Name }}(in []*{{ .ElemName }}) []*{{ .ElemName }} {
+	res := make([]*{{ .ElemName }}, 0, len(in))
+	for _, e := range in {
+		if e != nil {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Deref{{ .Name }} returns the values of the non nil elements of in.
+func Deref{{ .Name }}(in []*{{ .ElemName }}) []{{ .ElemName }} {
+	res := make([]{{ .ElemName }}, 0, len(in))
+	for _, e := range in {
+		if e != nil {
+			res = append(res, *e)
+		}
+	}
+	return res
+}
+{{ end }}`
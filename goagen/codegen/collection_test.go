@@ -0,0 +1,127 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoCollectionType", func() {
+	var elem *design.AttributeDefinition
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		id := &design.UserTypeDefinition{
+			TypeName:            "ID",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+		}
+		elem = &design.AttributeDefinition{Type: id}
+	})
+
+	JustBeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName:            "IDs",
+			AttributeDefinition: &design.AttributeDefinition{Type: &design.Array{ElemType: elem}},
+		}
+	})
+
+	It("produces the collection type and its methods", func() {
+		Ω(codegen.GoCollectionType(ut)).Should(Equal(idsCollectionCode))
+	})
+
+	It("renames the methods that collide with other generated methods", func() {
+		code := codegen.GoCollectionType(ut, "Len", "Len2")
+		Ω(code).Should(ContainSubstring("func (c IDs) Len3() int {"))
+		Ω(code).Should(ContainSubstring("func (c IDs) Contains(v ID) bool {"))
+	})
+
+	Context("with object elements", func() {
+		BeforeEach(func() {
+			elem = &design.AttributeDefinition{Type: design.Object{}}
+		})
+
+		It("does not produce a Contains method", func() {
+			code := codegen.GoCollectionType(ut)
+			Ω(code).Should(ContainSubstring("type IDs []*struct {\n}"))
+			Ω(code).ShouldNot(ContainSubstring("Contains"))
+		})
+	})
+
+	Context("with object user type elements", func() {
+		BeforeEach(func() {
+			elem = &design.AttributeDefinition{Type: &design.UserTypeDefinition{
+				TypeName:            "Bottle",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+			}}
+		})
+
+		It("produces the Compact and Deref functions", func() {
+			Ω(codegen.GoCollectionType(ut)).Should(HaveSuffix(compactIDsCode))
+		})
+	})
+
+	It("does not produce the Compact and Deref functions for other elements", func() {
+		code := codegen.GoCollectionType(ut)
+		Ω(code).ShouldNot(ContainSubstring("Compact"))
+		Ω(code).ShouldNot(ContainSubstring("Deref"))
+	})
+
+	It("rejects non array types", func() {
+		Ω(func() { codegen.GoCollectionType(elem.Type.(*design.UserTypeDefinition)) }).Should(Panic())
+	})
+})
+
+const idsCollectionCode = `// IDs is a collection of ID values.
+type IDs []ID
+
+// Len returns the number of elements in the collection.
+func (c IDs) Len() int {
+	return len(c)
+}
+
+// Contains returns true if the collection contains v.
+func (c IDs) Contains(v ID) bool {
+	for _, e := range c {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the elements of the collection for which f returns true.
+func (c IDs) Filter(f func(ID) bool) IDs {
+	var res IDs
+	for _, e := range c {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+`
+
+const compactIDsCode = `
+// CompactIDs returns the non nil elements of in.
+func CompactIDs(in []*Bottle) []*Bottle {
+	res := make([]*Bottle, 0, len(in))
+	for _, e := range in {
+		if e != nil {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// DerefIDs returns the values of the non nil elements of in.
+func DerefIDs(in []*Bottle) []Bottle {
+	res := make([]Bottle, 0, len(in))
+	for _, e := range in {
+		if e != nil {
+			res = append(res, *e)
+		}
+	}
+	return res
+}
+`
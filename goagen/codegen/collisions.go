@@ -0,0 +1,38 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// CheckMethodCollisions returns an error if any of the struct fields generated for the attributes
+// of the given object user type has the same name as one of the given methods. methods lists the
+// names of the methods produced by the generators that are active for the type, e.g. "Validate"
+// or "Clone", so that only the relevant collisions are reported. Go does not allow a struct to
+// have a field and a method with the same name, the "struct:field:name" metadata can be used to
+// rename the colliding fields.
+func CheckMethodCollisions(ut *design.UserTypeDefinition, methods ...string) error {
+	if !ut.IsObject() || len(methods) == 0 {
+		return nil
+	}
+	generated := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		generated[m] = true
+	}
+	var collisions []string
+	ut.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if field := goFieldName(n, catt); generated[field] {
+			collisions = append(collisions, fmt.Sprintf("%#v (field %#v)", n, field))
+		}
+		return nil
+	})
+	if len(collisions) == 0 {
+		return nil
+	}
+	sort.Strings(collisions)
+	return fmt.Errorf("the following attributes of type %s produce fields that collide with generated methods, use the struct:field:name metadata to rename them: %s",
+		ut.TypeName, strings.Join(collisions, ", "))
+}
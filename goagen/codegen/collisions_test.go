@@ -0,0 +1,42 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckMethodCollisions", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":     &design.AttributeDefinition{Type: design.String},
+					"validate": &design.AttributeDefinition{Type: design.Boolean},
+					"clone":    &design.AttributeDefinition{Type: design.String},
+				},
+			},
+		}
+	})
+
+	It("reports the fields that collide with the generated methods", func() {
+		err := codegen.CheckMethodCollisions(ut, "Validate", "Clone")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(`the following attributes of type bottle produce fields that collide with generated methods, use the struct:field:name metadata to rename them: "clone" (field "Clone"), "validate" (field "Validate")`))
+	})
+
+	It("only considers the given methods", func() {
+		Ω(codegen.CheckMethodCollisions(ut, "Validate", "String")).Should(MatchError(ContainSubstring(`"validate"`)))
+		Ω(codegen.CheckMethodCollisions(ut, "Equal")).Should(Succeed())
+	})
+
+	It("accepts fields renamed with struct:field:name", func() {
+		ut.Type.ToObject()["validate"].Metadata = dslengine.MetadataDefinition{"struct:field:name": {"shouldValidate"}}
+		Ω(codegen.CheckMethodCollisions(ut, "Validate")).Should(Succeed())
+	})
+})
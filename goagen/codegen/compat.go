@@ -0,0 +1,105 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Incompatibility describes a change made to a type that breaks the compatibility of the
+// generated code or of the wire format with the previous version of the type.
+type Incompatibility struct {
+	// Path is the path to the changed attribute starting with the name of the type, e.g.
+	// "bottle.origin.country". Array and hash elements are denoted with "[*]" and hash keys
+	// with "[key]".
+	Path string
+	// Description describes the change.
+	Description string
+}
+
+// String returns the path and the description of the incompatibility.
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Description)
+}
+
+// CompareTypes compares two versions of a user type, from and to, and returns the changes that
+// break compatibility: attributes whose kind changes (e.g. from integer to string), attributes
+// that are removed and attributes that become required or that are added as required. Attributes are
+// compared structurally so that renaming a user type used by an attribute is not reported.
+// The incompatibilities are sorted by path.
+func CompareTypes(from, to *design.UserTypeDefinition) []Incompatibility {
+	var res []Incompatibility
+	compareAttributes(from.AttributeDefinition, to.AttributeDefinition, from.TypeName, make(map[string]bool), &res)
+	sort.Stable(byPath(res))
+	return res
+}
+
+// byPath makes it possible to sort incompatibilities by path.
+type byPath []Incompatibility
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byPath) Less(i, j int) bool { return b[i].Path < b[j].Path }
+
+// compareAttributes appends the incompatibilities between the from and to versions of the
+// attribute at path to res. seen records the pairs of user types being compared to break cycles.
+func compareAttributes(from, to *design.AttributeDefinition, path string, seen map[string]bool, res *[]Incompatibility) {
+	if isUserType(from.Type) && isUserType(to.Type) {
+		key := userTypeName(from.Type) + "/" + userTypeName(to.Type)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		defer delete(seen, key)
+	}
+	from, to = underlyingAttribute(from), underlyingAttribute(to)
+	if from.Type.Kind() != to.Type.Kind() {
+		*res = append(*res, Incompatibility{
+			Path:        path,
+			Description: fmt.Sprintf("type changed from %s to %s", from.Type.Name(), to.Type.Name()),
+		})
+		return
+	}
+	switch from.Type.Kind() {
+	case design.ArrayKind:
+		compareAttributes(from.Type.ToArray().ElemType, to.Type.ToArray().ElemType, path+"[*]", seen, res)
+	case design.HashKind:
+		fh, th := from.Type.ToHash(), to.Type.ToHash()
+		compareAttributes(fh.KeyType, th.KeyType, path+"[key]", seen, res)
+		compareAttributes(fh.ElemType, th.ElemType, path+"[*]", seen, res)
+	case design.ObjectKind:
+		fo, tobj := from.Type.ToObject(), to.Type.ToObject()
+		fo.IterateAttributes(func(n string, fatt *design.AttributeDefinition) error {
+			p := path + "." + n
+			tatt, ok := tobj[n]
+			if !ok {
+				*res = append(*res, Incompatibility{Path: p, Description: "attribute removed"})
+				return nil
+			}
+			if !from.IsRequired(n) && to.IsRequired(n) {
+				*res = append(*res, Incompatibility{Path: p, Description: "attribute became required"})
+			}
+			compareAttributes(fatt, tatt, p, seen, res)
+			return nil
+		})
+		tobj.IterateAttributes(func(n string, _ *design.AttributeDefinition) error {
+			if _, ok := fo[n]; !ok && to.IsRequired(n) {
+				*res = append(*res, Incompatibility{Path: path + "." + n, Description: "required attribute added"})
+			}
+			return nil
+		})
+	}
+}
+
+// underlyingAttribute returns the attribute definition of att's type if it is a user type, att
+// otherwise.
+func underlyingAttribute(att *design.AttributeDefinition) *design.AttributeDefinition {
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		return actual.AttributeDefinition
+	case *design.MediaTypeDefinition:
+		return actual.AttributeDefinition
+	}
+	return att
+}
@@ -0,0 +1,93 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CompareTypes", func() {
+	var from, to *design.UserTypeDefinition
+
+	newBottle := func(o design.Object, required ...string) *design.UserTypeDefinition {
+		att := &design.AttributeDefinition{Type: o}
+		if len(required) > 0 {
+			att.Validation = &dslengine.ValidationDefinition{Required: required}
+		}
+		return &design.UserTypeDefinition{TypeName: "bottle", AttributeDefinition: att}
+	}
+
+	BeforeEach(func() {
+		origin := &design.UserTypeDefinition{
+			TypeName: "origin",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+				"country": &design.AttributeDefinition{Type: design.String},
+			}},
+		}
+		from = newBottle(design.Object{
+			"name":   &design.AttributeDefinition{Type: design.String},
+			"rating": &design.AttributeDefinition{Type: design.Integer},
+			"origin": &design.AttributeDefinition{Type: origin},
+			"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+		}, "name")
+	})
+
+	It("accepts identical types", func() {
+		Ω(codegen.CompareTypes(from, from)).Should(BeEmpty())
+	})
+
+	It("accepts renamed user types and new optional attributes", func() {
+		place := &design.UserTypeDefinition{
+			TypeName: "place",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+				"country": &design.AttributeDefinition{Type: design.String},
+			}},
+		}
+		to = newBottle(design.Object{
+			"name":    &design.AttributeDefinition{Type: design.String},
+			"rating":  &design.AttributeDefinition{Type: design.Integer},
+			"origin":  &design.AttributeDefinition{Type: place},
+			"tags":    &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+			"comment": &design.AttributeDefinition{Type: design.String},
+		}, "name")
+		Ω(codegen.CompareTypes(from, to)).Should(BeEmpty())
+	})
+
+	It("reports incompatible changes", func() {
+		to = newBottle(design.Object{
+			"name":   &design.AttributeDefinition{Type: design.String},
+			"rating": &design.AttributeDefinition{Type: design.String},
+			"origin": &design.AttributeDefinition{Type: design.Object{
+				"country": &design.AttributeDefinition{Type: design.Integer},
+			}},
+			"vintage": &design.AttributeDefinition{Type: design.Integer},
+		}, "name", "origin", "vintage")
+		Ω(codegen.CompareTypes(from, to)).Should(Equal([]codegen.Incompatibility{
+			{Path: "bottle.origin", Description: "attribute became required"},
+			{Path: "bottle.origin.country", Description: "type changed from string to integer"},
+			{Path: "bottle.rating", Description: "type changed from integer to string"},
+			{Path: "bottle.tags", Description: "attribute removed"},
+			{Path: "bottle.vintage", Description: "required attribute added"},
+		}))
+	})
+
+	It("reports changes of array elements", func() {
+		to = newBottle(design.Object{
+			"name":   &design.AttributeDefinition{Type: design.String},
+			"rating": &design.AttributeDefinition{Type: design.Integer},
+			"origin": from.Type.ToObject()["origin"],
+			"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}}},
+		}, "name")
+		res := codegen.CompareTypes(from, to)
+		Ω(res).Should(HaveLen(1))
+		Ω(res[0].String()).Should(Equal("bottle.tags[*]: type changed from string to integer"))
+	})
+
+	It("handles recursive types", func() {
+		node := &design.UserTypeDefinition{TypeName: "node", AttributeDefinition: &design.AttributeDefinition{}}
+		node.Type = design.Object{"next": &design.AttributeDefinition{Type: node}}
+		Ω(codegen.CompareTypes(node, node)).Should(BeEmpty())
+	})
+})
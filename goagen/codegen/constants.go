@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+)
+
+// GoRangeConstants produces the Go code that declares constants for the minimum and maximum
+// values of the numeric attributes of the given user type that have range validations. The
+// constants are named after the type and the attribute, e.g. "BottleRatingMax". The user type may
+// also be a numeric primitive in which case the constants are named after the type only.
+// The function returns the empty string if there is no range validation.
+func GoRangeConstants(ut *design.UserTypeDefinition) string {
+	var buf bytes.Buffer
+	typeName := Goify(ut.TypeName, true)
+	writeRangeConstants(&buf, typeName, ut.AttributeDefinition)
+	if o := ut.Type.ToObject(); o != nil {
+		o.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+			writeRangeConstants(&buf, typeName+Goify(n, true), att)
+			return nil
+		})
+	}
+	if buf.Len() == 0 {
+		return ""
+	}
+	return "const (\n" + buf.String() + ")\n"
+}
+
+// GoFieldNameConstants produces the Go code that declares one constant per attribute of the given
+// object user type set to the name of the attribute in the design, e.g.
+// `BottleFieldNameRating = "rating"`. The constants are named after the type and the struct field
+// generated for the attribute so that downstream code such as query builders can refer to the
+// attributes symbolically. The function returns the empty string if the type has no attribute.
+func GoFieldNameConstants(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: field name constants require an object user type")
+	}
+	var buf bytes.Buffer
+	typeName := Goify(ut.TypeName, true)
+	ut.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		buf.WriteString(fmt.Sprintf("\t%sFieldName%s = %q\n", typeName, goFieldName(n, att), n))
+		return nil
+	})
+	if buf.Len() == 0 {
+		return ""
+	}
+	return "const (\n" + buf.String() + ")\n"
+}
+
+// writeRangeConstants writes the constant declarations for the range validations of att if it
+// is numeric.
+func writeRangeConstants(buf *bytes.Buffer, name string, att *design.AttributeDefinition) {
+	if att.Validation == nil {
+		return
+	}
+	kind := att.Type.Kind()
+	if ut, ok := att.Type.(*design.UserTypeDefinition); ok {
+		kind = ut.Type.Kind()
+	}
+	if kind != design.IntegerKind && kind != design.NumberKind {
+		return
+	}
+	if min := att.Validation.Minimum; min != nil {
+		buf.WriteString(fmt.Sprintf("\t%sMin = %s\n", name, numericLiteral(*min, kind)))
+	}
+	if max := att.Validation.Maximum; max != nil {
+		buf.WriteString(fmt.Sprintf("\t%sMax = %s\n", name, numericLiteral(*max, kind)))
+	}
+}
+
+// numericLiteral renders the given value as a Go literal for the given numeric kind. Numbers are
+// rendered with the smallest precision that round-trips.
+func numericLiteral(v float64, kind design.Kind) string {
+	if kind == design.IntegerKind {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	lit := strconv.FormatFloat(v, 'g', -1, 64)
+	if _, err := strconv.Atoi(lit); err == nil {
+		// make sure the constant is untyped float
+		lit += ".0"
+	}
+	return lit
+}
@@ -0,0 +1,102 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoRangeConstants", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+
+	JustBeforeEach(func() {
+		code = codegen.GoRangeConstants(ut)
+	})
+
+	Context("given an object with range validations", func() {
+		BeforeEach(func() {
+			min, max, fmax := 0.0, 100.0, 0.1
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"rating": &design.AttributeDefinition{
+							Type:       design.Integer,
+							Validation: &dslengine.ValidationDefinition{Minimum: &min, Maximum: &max},
+						},
+						"ratio": &design.AttributeDefinition{
+							Type:       design.Number,
+							Validation: &dslengine.ValidationDefinition{Minimum: &min, Maximum: &fmax},
+						},
+						"name": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Pattern: "^a"},
+						},
+					},
+				},
+			}
+		})
+
+		It("generates the min and max constants", func() {
+			Ω(code).Should(Equal(`const (
+	BottleRatingMin = 0
+	BottleRatingMax = 100
+	BottleRatioMin = 0.0
+	BottleRatioMax = 0.1
+)
+`))
+		})
+	})
+
+	Context("given an object with no range validation", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				},
+			}
+		})
+
+		It("generates nothing", func() {
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("GoFieldNameConstants", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":       &design.AttributeDefinition{Type: design.String},
+					"created_at": &design.AttributeDefinition{Type: design.DateTime},
+					"vintage": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:field:name": {"Year"}},
+					},
+				},
+			},
+		}
+	})
+
+	It("maps the field names to the attribute names", func() {
+		Ω(codegen.GoFieldNameConstants(ut)).Should(Equal(`const (
+	BottleFieldNameCreatedAt = "created_at"
+	BottleFieldNameName = "name"
+	BottleFieldNameYear = "vintage"
+)
+`))
+	})
+
+	It("generates nothing for types with no attribute", func() {
+		ut.Type = design.Object{}
+		Ω(codegen.GoFieldNameConstants(ut)).Should(BeEmpty())
+	})
+})
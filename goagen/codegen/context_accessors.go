@@ -0,0 +1,66 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var contextAccessorsT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if contextAccessorsT, err = template.New("contextAccessors").Parse(contextAccessorsTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoContextAccessors produces the Go code that stores values of the given user types in contexts
+// and retrieves them, e.g. "ContextWithClaims" and "ClaimsFromContext". The context keys are
+// constants of the unexported "contextKey" type so that they cannot collide with the keys defined
+// by other packages, the code must thus be generated once per package. GoContextAccessors panics
+// if two user types produce the same Go type name. The generated code requires the "context"
+// package.
+func GoContextAccessors(types []*design.UserTypeDefinition) string {
+	type accessor struct {
+		Name, Key, Type string
+	}
+	accessors := make([]*accessor, len(types))
+	names := make(map[string]string, len(types))
+	for i, ut := range types {
+		name := GoTypeName(ut, nil, 0, false)
+		if other, ok := names[name]; ok {
+			panic(fmt.Sprintf("user types %s and %s produce the same context accessors %sFromContext", other, ut.TypeName, name))
+		}
+		names[name] = ut.TypeName
+		accessors[i] = &accessor{
+			Name: name,
+			Key:  Goify(name, false) + "ContextKey",
+			Type: GoTypeRef(ut, nil, 0, false),
+		}
+	}
+	return RunTemplate(contextAccessorsT, accessors)
+}
+
+const contextAccessorsTmpl = `// contextKey is the type of the keys of the values stored in contexts, it is unexported to avoid
+// collisions with the keys defined in other packages.
+type contextKey int
+
+const (
+{{ range $i, $a := . }}	// {{ $a.Key }} is the key of the {{ $a.Name }} values stored in contexts.
+	{{ $a.Key }}{{ if not $i }} contextKey = iota + 1{{ end }}
+{{ end }})
+{{ range . }}
+// ContextWith{{ .Name }} returns a copy of ctx that holds v.
+func ContextWith{{ .Name }}(ctx context.Context, v {{ .Type }}) context.Context {
+	return context.WithValue(ctx, {{ .Key }}, v)
+}
+
+// {{ .Name }}FromContext returns the {{ .Name }} value held by ctx if any.
+func {{ .Name }}FromContext(ctx context.Context) ({{ .Type }}, bool) {
+	v, ok := ctx.Value({{ .Key }}).({{ .Type }})
+	return v, ok
+}
+{{ end }}`
@@ -0,0 +1,73 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoContextAccessors", func() {
+	var types []*design.UserTypeDefinition
+
+	BeforeEach(func() {
+		types = []*design.UserTypeDefinition{
+			{
+				TypeName:            "claims",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"sub": {Type: design.String}}},
+			},
+			{
+				TypeName:            "request_id",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+			},
+		}
+	})
+
+	It("produces the keys and the accessors", func() {
+		Ω(codegen.GoContextAccessors(types)).Should(Equal(contextAccessorsCode))
+	})
+
+	Context("with types that have the same Go name", func() {
+		BeforeEach(func() {
+			types[1].TypeName = "Claims"
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoContextAccessors(types) }).Should(Panic())
+		})
+	})
+})
+
+const contextAccessorsCode = `// contextKey is the type of the keys of the values stored in contexts, it is unexported to avoid
+// collisions with the keys defined in other packages.
+type contextKey int
+
+const (
+	// claimsContextKey is the key of the Claims values stored in contexts.
+	claimsContextKey contextKey = iota + 1
+	// requestIDContextKey is the key of the RequestID values stored in contexts.
+	requestIDContextKey
+)
+
+// ContextWithClaims returns a copy of ctx that holds v.
+func ContextWithClaims(ctx context.Context, v *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey, v)
+}
+
+// ClaimsFromContext returns the Claims value held by ctx if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	v, ok := ctx.Value(claimsContextKey).(*Claims)
+	return v, ok
+}
+
+// ContextWithRequestID returns a copy of ctx that holds v.
+func ContextWithRequestID(ctx context.Context, v RequestID) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, v)
+}
+
+// RequestIDFromContext returns the RequestID value held by ctx if any.
+func RequestIDFromContext(ctx context.Context) (RequestID, bool) {
+	v, ok := ctx.Value(requestIDContextKey).(RequestID)
+	return v, ok
+}
+`
@@ -0,0 +1,214 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var pointerValueConversionT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if pointerValueConversionT, err = template.New("pointerValueConversion").Parse(pointerValueConversionTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoPointerValueConversion produces the Go code of the ToValue and FromValue methods that convert
+// the struct generated for ptrType with pointer fields (see PointerFields) into the struct
+// generated for valType with value fields (see ValueFields) and back, e.g. to share the code of
+// the HTTP and gRPC representations of a type. Both types must be objects that define the same
+// attributes. ToValue dereferences pointers and uses the zero value for nil pointers, FromValue
+// takes the address of the values. Fields whose types are object user types are converted with the
+// ToValue and FromValue methods of the types which must thus be generated as well, the elements
+// of arrays and hashes and the fields of inline objects are converted recursively.
+// GoPointerValueConversion panics if the types of an attribute differ or cannot be converted.
+func GoPointerValueConversion(ptrType, valType *design.UserTypeDefinition) string {
+	if !ptrType.IsObject() || !valType.IsObject() {
+		panic("goa bug: pointer to value conversion requires object user types")
+	}
+	var to, from bytes.Buffer
+	patt, vatt := ptrType.AttributeDefinition, valType.AttributeDefinition
+	tc := &converter{toValue: true, ptrName: ptrType.TypeName, valName: valType.TypeName, taken: map[string]bool{"ut": true, "v": true}}
+	tc.writeFields(&to, patt, vatt, "ut", "v", 1)
+	fc := &converter{ptrName: ptrType.TypeName, valName: valType.TypeName, taken: map[string]bool{"ut": true, "v": true}}
+	fc.writeFields(&from, vatt, patt, "v", "ut", 1)
+	data := map[string]interface{}{
+		"Name":      GoTypeName(ptrType, nil, 0, false),
+		"Value":     GoTypeName(valType, nil, 0, false),
+		"ToValue":   to.String(),
+		"FromValue": from.String(),
+	}
+	return RunTemplate(pointerValueConversionT, data)
+}
+
+// converter writes the code of the ToValue or FromValue method produced by
+// GoPointerValueConversion.
+type converter struct {
+	// toValue is true if the code converts the pointer struct into the value struct.
+	toValue bool
+	// ptrName and valName are the names of the converted user types used in error messages.
+	ptrName, valName string
+	// taken records the names of the variables declared by the method.
+	taken map[string]bool
+}
+
+// modes returns the struct modes of the source and target of the conversion.
+func (c *converter) modes() (StructMode, StructMode) {
+	if c.toValue {
+		return PointerFields, ValueFields
+	}
+	return ValueFields, PointerFields
+}
+
+// writeFields writes the code that converts the fields of the struct src defined by satt into the
+// fields of the struct dst defined by datt. satt and datt must be objects.
+func (c *converter) writeFields(buf *bytes.Buffer, satt, datt *design.AttributeDefinition, src, dst string, depth int) {
+	smode, dmode := c.modes()
+	patt, vatt := satt, datt
+	if !c.toValue {
+		patt, vatt = datt, satt
+	}
+	vo := vatt.Type.ToObject()
+	patt.Type.ToObject().IterateAttributes(func(n string, pfield *design.AttributeDefinition) error {
+		vfield, ok := vo[n]
+		if !ok {
+			panic(fmt.Sprintf("attribute %#v of %s is missing from %s", n, c.ptrName, c.valName))
+		}
+		if pfield.Type.Kind() != vfield.Type.Kind() {
+			panic(fmt.Sprintf("attribute %#v of %s is a %s but it is a %s in %s", n, c.ptrName,
+				pfield.Type.Name(), vfield.Type.Name(), c.valName))
+		}
+		sfield, dfield := pfield, vfield
+		if !c.toValue {
+			sfield, dfield = vfield, pfield
+		}
+		sname, dname := src+"."+goFieldName(n, sfield), dst+"."+goFieldName(n, dfield)
+		sref := fieldTypeRef(satt, n, goTypeDef(sfield, 0, true, false, false, smode, nil), false, smode)
+		dref := fieldTypeRef(datt, n, goTypeDef(dfield, 0, true, false, false, dmode, nil), false, dmode)
+		switch {
+		case sref == dref:
+			writeLine(buf, depth, "%s = %s", dname, sname)
+		case sfield.Type.IsPrimitive() && sref == "*"+dref:
+			writeLine(buf, depth, "if %s != nil {", sname)
+			writeLine(buf, depth+1, "%s = *%s", dname, sname)
+			writeLine(buf, depth, "}")
+		case sfield.Type.IsPrimitive() && "*"+sref == dref:
+			tmp := uniqueName(Goify(n, false), c.taken)
+			writeLine(buf, depth, "%s := %s", tmp, sname)
+			writeLine(buf, depth, "%s = &%s", dname, tmp)
+		default:
+			c.writeValue(buf, sfield, dfield, sname, dname, n, depth)
+		}
+		return nil
+	})
+}
+
+// writeValue writes the code that assigns the conversion of the value src defined by satt to dst
+// defined by datt. n is the name of the converted attribute.
+func (c *converter) writeValue(buf *bytes.Buffer, satt, datt *design.AttributeDefinition, src, dst, n string, depth int) {
+	smode, dmode := c.modes()
+	// the types are only written in make calls indented by one more tab
+	sref, dref := goValueTypeRefMode(satt, depth+1, smode), goValueTypeRefMode(datt, depth+1, dmode)
+	switch {
+	case sref == dref:
+		writeLine(buf, depth, "%s = %s", dst, src)
+	case isObjectUserType(satt.Type):
+		if c.toValue {
+			writeToValue(buf, datt, src, dst, uniqueName(Goify(n, false), c.taken), depth)
+		} else {
+			writeFromValue(buf, datt, satt, src, dst, depth)
+		}
+	case satt.Type.IsArray():
+		i, e := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = make(%s, len(%s))", dst, dref, src)
+		writeLine(buf, depth+1, "for %s, %s := range %s {", i, e, src)
+		c.writeValue(buf, satt.Type.ToArray().ElemType, datt.Type.ToArray().ElemType, e, fmt.Sprintf("%s[%s]", dst, i), n, depth+2)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth, "}")
+	case satt.Type.IsHash():
+		k, e, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth), fmt.Sprintf("c%d", depth)
+		selem, delem := satt.Type.ToHash().ElemType, datt.Type.ToHash().ElemType
+		writeLine(buf, depth, "if %s != nil {", src)
+		writeLine(buf, depth+1, "%s = make(%s, len(%s))", dst, dref, src)
+		writeLine(buf, depth+1, "for %s, %s := range %s {", k, e, src)
+		writeLine(buf, depth+2, "var %s %s", v, goValueTypeRefMode(delem, depth+2, dmode))
+		c.writeValue(buf, selem, delem, e, v, n, depth+2)
+		writeLine(buf, depth+2, "%s[%s] = %s", dst, k, v)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth, "}")
+	case satt.Type.IsObject():
+		// inline objects are pointers with PointerFields and values with ValueFields
+		inner, target := depth, dst
+		if isPointerObject(satt.Type, smode) {
+			writeLine(buf, depth, "if %s != nil {", src)
+			inner++
+		}
+		if isPointerObject(datt.Type, dmode) {
+			target = uniqueName(Goify(n, false), c.taken)
+			writeLine(buf, inner, "%s := new(%s)", target, GoTypeDefMode(datt, inner, true, false, dmode))
+		}
+		c.writeFields(buf, satt, datt, src, target, inner)
+		if target != dst {
+			writeLine(buf, inner, "%s = %s", dst, target)
+		}
+		if inner != depth {
+			writeLine(buf, depth, "}")
+		}
+	default:
+		panic(fmt.Sprintf("attribute %#v of %s cannot be converted from %s to %s", n, c.ptrName, sref, dref))
+	}
+}
+
+// isObjectUserType returns true if dt is a user type or a media type whose values are objects.
+func isObjectUserType(dt design.DataType) bool {
+	return isUserType(dt) && dt.IsObject()
+}
+
+// writeToValue writes the code that assigns the value produced by the ToValue method of the
+// object pointer src to dst, vatt is the value attribute of dst. tmp holds the value if dst is a
+// pointer.
+func writeToValue(buf *bytes.Buffer, vatt *design.AttributeDefinition, src, dst, tmp string, depth int) {
+	if !isPointerObject(vatt.Type, ValueFields) {
+		writeLine(buf, depth, "%s = %s.ToValue()", dst, src)
+		return
+	}
+	writeLine(buf, depth, "if %s != nil {", src)
+	writeLine(buf, depth+1, "%s := %s.ToValue()", tmp, src)
+	writeLine(buf, depth+1, "%s = &%s", dst, tmp)
+	writeLine(buf, depth, "}")
+}
+
+// writeFromValue writes the code that allocates the object pointer dst and initializes it from
+// the value src with its FromValue method, patt and vatt are the attributes of dst and src.
+func writeFromValue(buf *bytes.Buffer, patt, vatt *design.AttributeDefinition, src, dst string, depth int) {
+	name := GoTypeName(patt.Type, nil, 0, false)
+	if !isPointerObject(vatt.Type, ValueFields) {
+		writeLine(buf, depth, "%s = new(%s)", dst, name)
+		writeLine(buf, depth, "%s.FromValue(%s)", dst, src)
+		return
+	}
+	writeLine(buf, depth, "if %s != nil {", src)
+	writeLine(buf, depth+1, "%s = new(%s)", dst, name)
+	writeLine(buf, depth+1, "%s.FromValue(*%s)", dst, src)
+	writeLine(buf, depth, "}")
+}
+
+const pointerValueConversionTmpl = `// ToValue converts the {{ .Name }} into a {{ .Value }}, nil pointers produce zero values.
+func (ut *{{ .Name }}) ToValue() {{ .Value }} {
+	var v {{ .Value }}
+	if ut == nil {
+		return v
+	}
+{{ .ToValue }}	return v
+}
+
+// FromValue sets the fields of the {{ .Name }} from v.
+func (ut *{{ .Name }}) FromValue(v {{ .Value }}) {
+{{ .FromValue }}}
+`
@@ -0,0 +1,182 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPointerValueConversion", func() {
+	var ptrType, valType *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		bottle := func(name string, origin *design.UserTypeDefinition) *design.UserTypeDefinition {
+			return &design.UserTypeDefinition{
+				TypeName: name,
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name":    &design.AttributeDefinition{Type: design.String},
+						"rating":  &design.AttributeDefinition{Type: design.Integer},
+						"tags":    &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+						"origin":  &design.AttributeDefinition{Type: origin},
+						"origins": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: origin}}},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+			}
+		}
+		origin := func(name string) *design.UserTypeDefinition {
+			return &design.UserTypeDefinition{
+				TypeName:            name,
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"country": &design.AttributeDefinition{Type: design.String}}},
+			}
+		}
+		ptrType = bottle("Bottle", origin("Origin"))
+		valType = bottle("BottleValue", origin("OriginValue"))
+	})
+
+	It("converts the fields both ways", func() {
+		Ω(codegen.GoPointerValueConversion(ptrType, valType)).Should(Equal(pointerValueConversionCode))
+	})
+
+	Context("with recursive types", func() {
+		BeforeEach(func() {
+			node := func(name string) *design.UserTypeDefinition {
+				ut := &design.UserTypeDefinition{
+					TypeName:            name,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+				}
+				ut.Type.ToObject()["next"] = &design.AttributeDefinition{Type: ut}
+				return ut
+			}
+			ptrType, valType = node("Node"), node("NodeValue")
+		})
+
+		It("keeps nil pointers", func() {
+			Ω(codegen.GoPointerValueConversion(ptrType, valType)).Should(Equal(recursiveConversionCode))
+		})
+	})
+
+	Context("with hashes and inline objects", func() {
+		var elemPtr, elemVal *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			elem := func(name string) *design.UserTypeDefinition {
+				return &design.UserTypeDefinition{
+					TypeName:            name,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"id": &design.AttributeDefinition{Type: design.Integer}}},
+				}
+			}
+			bottle := func(name string, elem *design.UserTypeDefinition) *design.UserTypeDefinition {
+				inline := func() *design.AttributeDefinition {
+					return &design.AttributeDefinition{Type: design.Object{
+						"x":    &design.AttributeDefinition{Type: design.Integer},
+						"tags": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					}}
+				}
+				return &design.UserTypeDefinition{
+					TypeName: name,
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"groups": &design.AttributeDefinition{Type: &design.Hash{
+								KeyType:  &design.AttributeDefinition{Type: design.String},
+								ElemType: &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: elem}}},
+							}},
+							"meta":  inline(),
+							"items": &design.AttributeDefinition{Type: &design.Array{ElemType: inline()}},
+							"index": &design.AttributeDefinition{Type: &design.Hash{
+								KeyType:  &design.AttributeDefinition{Type: design.String},
+								ElemType: inline(),
+							}},
+						},
+					},
+				}
+			}
+			elemPtr, elemVal = elem("Elem"), elem("ElemValue")
+			ptrType, valType = bottle("Bottle", elemPtr), bottle("BottleValue", elemVal)
+		})
+
+		It("generates code that compiles", func() {
+			code := "type Bottle " + codegen.GoTypeDef(ptrType, 0, true, false) + "\n\n" +
+				"type BottleValue " + codegen.GoTypeDefMode(valType, 0, true, false, codegen.ValueFields) + "\n\n" +
+				"type Elem " + codegen.GoTypeDef(elemPtr, 0, true, false) + "\n\n" +
+				"type ElemValue " + codegen.GoTypeDefMode(elemVal, 0, true, false, codegen.ValueFields) + "\n\n" +
+				codegen.GoPointerValueConversion(ptrType, valType) + "\n" +
+				codegen.GoPointerValueConversion(elemPtr, elemVal)
+			Ω(typeCheck(code)).Should(Succeed())
+		})
+	})
+
+	Context("with a missing attribute", func() {
+		BeforeEach(func() {
+			delete(valType.Type.ToObject(), "rating")
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoPointerValueConversion(ptrType, valType) }).Should(Panic())
+		})
+	})
+})
+
+const pointerValueConversionCode = `// ToValue converts the Bottle into a BottleValue, nil pointers produce zero values.
+func (ut *Bottle) ToValue() BottleValue {
+	var v BottleValue
+	if ut == nil {
+		return v
+	}
+	v.Name = ut.Name
+	v.Origin = ut.Origin.ToValue()
+	if ut.Origins != nil {
+		v.Origins = make([]OriginValue, len(ut.Origins))
+		for i1, e1 := range ut.Origins {
+			v.Origins[i1] = e1.ToValue()
+		}
+	}
+	if ut.Rating != nil {
+		v.Rating = *ut.Rating
+	}
+	v.Tags = ut.Tags
+	return v
+}
+
+// FromValue sets the fields of the Bottle from v.
+func (ut *Bottle) FromValue(v BottleValue) {
+	ut.Name = v.Name
+	ut.Origin = new(Origin)
+	ut.Origin.FromValue(v.Origin)
+	if v.Origins != nil {
+		ut.Origins = make([]*Origin, len(v.Origins))
+		for i1, e1 := range v.Origins {
+			ut.Origins[i1] = new(Origin)
+			ut.Origins[i1].FromValue(e1)
+		}
+	}
+	rating := v.Rating
+	ut.Rating = &rating
+	ut.Tags = v.Tags
+}
+`
+
+const recursiveConversionCode = `// ToValue converts the Node into a NodeValue, nil pointers produce zero values.
+func (ut *Node) ToValue() NodeValue {
+	var v NodeValue
+	if ut == nil {
+		return v
+	}
+	if ut.Next != nil {
+		next := ut.Next.ToValue()
+		v.Next = &next
+	}
+	return v
+}
+
+// FromValue sets the fields of the Node from v.
+func (ut *Node) FromValue(v NodeValue) {
+	if v.Next != nil {
+		ut.Next = new(Node)
+		ut.Next.FromValue(*v.Next)
+	}
+}
+`
@@ -0,0 +1,85 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+// timeFormatKey is the name of the metadata that defines the layout used to encode the values of
+// a date time user type.
+const timeFormatKey = "struct:time:format"
+
+var customTimeT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if customTimeT, err = template.New("customTime").Parse(customTimeTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoCustomTimeType produces the Go code that declares the struct generated for the given
+// DateTime user type if it defines the "struct:time:format" metadata, the empty string otherwise.
+// The value of the metadata is a layout of the time package, e.g. "2006-01-02". The struct embeds
+// time.Time and defines the MarshalJSON and UnmarshalJSON methods that encode the values as JSON
+// strings using the layout rather than RFC3339, the layout is also exposed as a constant named
+// after the type, e.g. "DateLayout". UnmarshalJSON leaves the value unchanged for JSON null.
+// GoCustomTimeType panics if the layout cannot parse the values it formats. The generated code
+// requires the "fmt", "strconv" and "time" packages.
+func GoCustomTimeType(ut *design.UserTypeDefinition) string {
+	if ut.Type.Kind() != design.DateTimeKind {
+		return ""
+	}
+	meta, ok := ut.Metadata[timeFormatKey]
+	if !ok {
+		return ""
+	}
+	if len(meta) == 0 || meta[0] == "" {
+		panic(fmt.Sprintf("%s metadata of type %s must define a layout", timeFormatKey, ut.TypeName))
+	}
+	layout := meta[0]
+	ref := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	if _, err := time.Parse(layout, ref.Format(layout)); err != nil {
+		panic(fmt.Sprintf("invalid %s layout %#v of type %s: %s", timeFormatKey, layout, ut.TypeName, err))
+	}
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Layout": layout,
+	}
+	return RunTemplate(customTimeT, data)
+}
+
+const customTimeTmpl = `{{ $name := .Name }}// {{ $name }}Layout is the layout used to encode {{ $name }} values.
+const {{ $name }}Layout = {{ printf "%q" .Layout }}
+
+// {{ $name }} is a time encoded with {{ $name }}Layout in JSON.
+type {{ $name }} struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t {{ $name }}) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.Format({{ $name }}Layout))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *{{ $name }}) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("{{ $name }} value must be a JSON string but got %s", data)
+	}
+	v, err := time.Parse({{ $name }}Layout, s)
+	if err != nil {
+		return err
+	}
+	t.Time = v
+	return nil
+}
+`
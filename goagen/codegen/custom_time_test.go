@@ -0,0 +1,73 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoCustomTimeType", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Date",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.DateTime,
+				Metadata: dslengine.MetadataDefinition{"struct:time:format": {"2006-01-02"}},
+			},
+		}
+	})
+
+	It("produces the wrapper using the layout", func() {
+		Ω(codegen.GoCustomTimeType(ut)).Should(Equal(customDateCode))
+	})
+
+	It("produces nothing without a layout", func() {
+		ut.Metadata = nil
+		Ω(codegen.GoCustomTimeType(ut)).Should(BeEmpty())
+	})
+
+	It("produces nothing for types that are not date times", func() {
+		ut.Type = design.String
+		Ω(codegen.GoCustomTimeType(ut)).Should(BeEmpty())
+	})
+
+	It("panics on empty layouts", func() {
+		ut.Metadata["struct:time:format"] = []string{""}
+		Ω(func() { codegen.GoCustomTimeType(ut) }).Should(Panic())
+	})
+})
+
+const customDateCode = `// DateLayout is the layout used to encode Date values.
+const DateLayout = "2006-01-02"
+
+// Date is a time encoded with DateLayout in JSON.
+type Date struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.Format(DateLayout))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("Date value must be a JSON string but got %s", data)
+	}
+	v, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return err
+	}
+	t.Time = v
+	return nil
+}
+`
@@ -0,0 +1,76 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+// GoDefaultLiteral produces the Go literal of the default value of the given attribute and
+// returns true if the attribute has a default value, the empty string and false otherwise. The
+// literal matches the type of the generated field: strings are quoted, numbers are rendered as
+// float literals, durations are rendered as a number of nanoseconds, e.g. "goa.Duration(1000000000)",
+// and defaults of user types are converted to the user type, e.g. "Rating(5)".
+// GoDefaultLiteral also returns false for the defaults of date time, UUID, big integer and raw
+// JSON attributes as their Go types have no literal form.
+func GoDefaultLiteral(att *design.AttributeDefinition) (string, bool) {
+	if att.DefaultValue == nil {
+		return "", false
+	}
+	if !att.Type.IsPrimitive() {
+		return printVal(att.Type, att.DefaultValue), true
+	}
+	t, conv := att.Type, ""
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		t, conv = actual.Type, GoTypeName(actual, nil, 0, false)
+	case *design.MediaTypeDefinition:
+		t, conv = actual.Type, GoTypeName(actual, nil, 0, false)
+	}
+	lit, ok := primitiveLiteral(t.Kind(), att.DefaultValue)
+	if !ok {
+		return "", false
+	}
+	if conv != "" {
+		lit = fmt.Sprintf("%s(%s)", conv, lit)
+	}
+	return lit, true
+}
+
+// primitiveLiteral renders the given value as a Go literal of the given primitive kind. It
+// returns false if values of the kind have no literal form.
+func primitiveLiteral(kind design.Kind, val interface{}) (string, bool) {
+	switch kind {
+	case design.BooleanKind:
+		return strconv.FormatBool(val.(bool)), true
+	case design.StringKind:
+		return strconv.Quote(val.(string)), true
+	case design.IntegerKind, design.NumberKind:
+		switch v := val.(type) {
+		case float32:
+			return numericLiteral(float64(v), kind), true
+		case float64:
+			return numericLiteral(v, kind), true
+		}
+		lit := fmt.Sprintf("%d", val)
+		if kind == design.NumberKind {
+			lit += ".0"
+		}
+		return lit, true
+	case design.DurationKind:
+		d, ok := val.(time.Duration)
+		if !ok {
+			var err error
+			if d, err = time.ParseDuration(val.(string)); err != nil {
+				panic(fmt.Sprintf("goa bug: invalid duration default value %#v", val))
+			}
+		}
+		return fmt.Sprintf("goa.Duration(%d)", int64(d)), true
+	case design.AnyKind:
+		return fmt.Sprintf("%#v", val), true
+	default:
+		return "", false
+	}
+}
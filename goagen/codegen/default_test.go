@@ -0,0 +1,134 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDefaultLiteral", func() {
+	var att *design.AttributeDefinition
+	var literal string
+	var ok bool
+
+	JustBeforeEach(func() {
+		literal, ok = codegen.GoDefaultLiteral(att)
+	})
+
+	Context("with no default value", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.Integer}
+		})
+
+		It("reports that there is no default", func() {
+			Ω(ok).Should(BeFalse())
+			Ω(literal).Should(BeEmpty())
+		})
+	})
+
+	Context("with an integer default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.Integer, DefaultValue: 5}
+		})
+
+		It("renders an integer literal", func() {
+			Ω(ok).Should(BeTrue())
+			Ω(literal).Should(Equal("5"))
+		})
+	})
+
+	Context("with an integral number default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.Number, DefaultValue: 5}
+		})
+
+		It("renders a float literal", func() {
+			Ω(literal).Should(Equal("5.0"))
+		})
+	})
+
+	Context("with a string default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.String, DefaultValue: "say \"hi\"\n"}
+		})
+
+		It("quotes and escapes the value", func() {
+			Ω(literal).Should(Equal(`"say \"hi\"\n"`))
+		})
+	})
+
+	Context("with a duration default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.Duration, DefaultValue: "1m30s"}
+		})
+
+		It("renders a goa.Duration conversion", func() {
+			Ω(literal).Should(Equal("goa.Duration(90000000000)"))
+		})
+	})
+
+	Context("with a boolean default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.Boolean, DefaultValue: false}
+		})
+
+		It("renders the boolean literal", func() {
+			Ω(ok).Should(BeTrue())
+			Ω(literal).Should(Equal("false"))
+		})
+	})
+
+	Context("with a user type default", func() {
+		BeforeEach(func() {
+			ut := &design.UserTypeDefinition{
+				TypeName:            "rating",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Number},
+			}
+			att = &design.AttributeDefinition{Type: ut, DefaultValue: 2.5}
+		})
+
+		It("converts the literal to the user type", func() {
+			Ω(literal).Should(Equal("Rating(2.5)"))
+		})
+	})
+
+	Context("with an array default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type:         &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+				DefaultValue: []interface{}{"a", "b"},
+			}
+		})
+
+		It("renders a composite literal", func() {
+			Ω(literal).Should(Equal(`[]string{"a", "b"}`))
+		})
+	})
+
+	Context("with a date time default", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{Type: design.DateTime, DefaultValue: "2016-01-02T15:04:05Z"}
+		})
+
+		It("reports that there is no literal", func() {
+			Ω(ok).Should(BeFalse())
+			Ω(literal).Should(BeEmpty())
+		})
+	})
+
+	Context("with enum values", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type:         design.String,
+				DefaultValue: "red",
+				Validation:   &dslengine.ValidationDefinition{Values: []interface{}{"red", "green"}},
+			}
+		})
+
+		It("renders the default", func() {
+			Ω(literal).Should(Equal(`"red"`))
+		})
+	})
+})
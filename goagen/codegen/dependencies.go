@@ -0,0 +1,78 @@
+package codegen
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// SortTypesByDependency returns the given user types sorted so that the types referred to by a
+// type come before it, e.g. to write the types that a file defines in a readable order. Types
+// that do not depend on each other are sorted by name and cycles are broken by ignoring the
+// references to the types being visited so that the result does not depend on the order of the
+// given types. References to types that are not in the list are ignored.
+func SortTypesByDependency(types []*design.UserTypeDefinition) []*design.UserTypeDefinition {
+	byName := make(map[string]*design.UserTypeDefinition, len(types))
+	names := make([]string, 0, len(types))
+	for _, ut := range types {
+		if _, ok := byName[ut.TypeName]; !ok {
+			names = append(names, ut.TypeName)
+		}
+		byName[ut.TypeName] = ut
+	}
+	sort.Strings(names)
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(names))
+	res := make([]*design.UserTypeDefinition, 0, len(names))
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		ut := byName[name]
+		for _, dep := range typeReferences(ut.Type) {
+			if _, ok := byName[dep]; ok && state[dep] == 0 {
+				visit(dep)
+			}
+		}
+		state[name] = visited
+		res = append(res, ut)
+	}
+	for _, name := range names {
+		if state[name] == 0 {
+			visit(name)
+		}
+	}
+	return res
+}
+
+// typeReferences returns the sorted names of the user types that dt refers to directly, that is
+// without looking into the definitions of the referred user types.
+func typeReferences(dt design.DataType) []string {
+	refs := make(map[string]bool)
+	collectTypeReferences(dt, refs)
+	names := make([]string, 0, len(refs))
+	for n := range refs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectTypeReferences records the names of the user types dt refers to in refs.
+func collectTypeReferences(dt design.DataType, refs map[string]bool) {
+	switch actual := dt.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		refs[userTypeName(actual)] = true
+	case *design.Array:
+		collectTypeReferences(actual.ElemType.Type, refs)
+	case *design.Hash:
+		collectTypeReferences(actual.KeyType.Type, refs)
+		collectTypeReferences(actual.ElemType.Type, refs)
+	case design.Object:
+		for _, att := range actual {
+			collectTypeReferences(att.Type, refs)
+		}
+	}
+}
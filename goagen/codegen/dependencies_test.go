@@ -0,0 +1,41 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SortTypesByDependency", func() {
+	newType := func(name string, o design.Object) *design.UserTypeDefinition {
+		return &design.UserTypeDefinition{TypeName: name, AttributeDefinition: &design.AttributeDefinition{Type: o}}
+	}
+
+	names := func(types []*design.UserTypeDefinition) []string {
+		res := make([]string, len(types))
+		for i, ut := range types {
+			res[i] = ut.TypeName
+		}
+		return res
+	}
+
+	It("puts dependencies first", func() {
+		country := newType("Country", design.Object{"name": &design.AttributeDefinition{Type: design.String}})
+		origin := newType("Origin", design.Object{"country": &design.AttributeDefinition{Type: country}})
+		bottle := newType("Bottle", design.Object{
+			"origins": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: origin}}},
+		})
+		account := newType("Account", design.Object{})
+		sorted := codegen.SortTypesByDependency([]*design.UserTypeDefinition{bottle, account, origin, country})
+		Ω(names(sorted)).Should(Equal([]string{"Account", "Country", "Origin", "Bottle"}))
+	})
+
+	It("breaks cycles deterministically", func() {
+		a := newType("A", design.Object{})
+		b := newType("B", design.Object{"a": &design.AttributeDefinition{Type: a}})
+		a.Type = design.Object{"b": &design.AttributeDefinition{Type: b}, "self": &design.AttributeDefinition{Type: a}}
+		Ω(names(codegen.SortTypesByDependency([]*design.UserTypeDefinition{a, b}))).Should(Equal([]string{"B", "A"}))
+		Ω(names(codegen.SortTypesByDependency([]*design.UserTypeDefinition{b, a}))).Should(Equal([]string{"B", "A"}))
+	})
+})
@@ -0,0 +1,66 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var descriptionMethodT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if descriptionMethodT, err = template.New("descriptionMethod").Parse(descriptionMethodTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// fieldDescription describes an attribute returned by the generated FieldDescription method.
+type fieldDescription struct {
+	// Name is the name of the attribute in the design.
+	Name string
+	// Description is the description of the attribute.
+	Description string
+}
+
+// GoDescriptionMethod produces the Go code of the Description method of the given user type which
+// returns the description of the type given in the design so that it may be served at runtime,
+// e.g. by an OpenAPI handler. The method of an object user type has a pointer receiver and comes
+// with a FieldDescription method that returns the description of the attribute with the given
+// design name, e.g. "created_at". Both methods return the empty string if there is no
+// description.
+func GoDescriptionMethod(ut *design.UserTypeDefinition) string {
+	data := map[string]interface{}{
+		"Name":        GoTypeName(ut, nil, 0, false),
+		"Description": ut.Description,
+	}
+	if ut.IsObject() {
+		var fields []*fieldDescription
+		ut.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if catt.Description != "" {
+				fields = append(fields, &fieldDescription{Name: n, Description: catt.Description})
+			}
+			return nil
+		})
+		data["Object"] = true
+		data["Fields"] = fields
+	}
+	return RunTemplate(descriptionMethodT, data)
+}
+
+const descriptionMethodTmpl = `{{ $recv := .Name }}{{ if .Object }}{{ $recv = printf "*%s" .Name }}{{ end }}// Description returns the description of the {{ .Name }} type.
+func ({{ $recv }}) Description() string {
+	return {{ printf "%q" .Description }}
+}
+{{ if .Object }}
+// FieldDescription returns the description of the {{ .Name }} attribute with the given name, the
+// empty string if there is no such attribute or if it has no description.
+func ({{ $recv }}) FieldDescription(name string) string {
+{{ if .Fields }}	switch name {
+{{ range .Fields }}	case {{ printf "%q" .Name }}:
+		return {{ printf "%q" .Description }}
+{{ end }}	}
+{{ end }}	return ""
+}
+{{ end }}`
@@ -0,0 +1,54 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDescriptionMethod", func() {
+	It("produces the description methods of object user types", func() {
+		ut := &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Description: "A bottle of \"wine\"",
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String, Description: "Name of bottle"},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+				},
+			},
+		}
+		Ω(codegen.GoDescriptionMethod(ut)).Should(Equal(bottleDescriptionCode))
+	})
+
+	It("produces the Description method of other user types", func() {
+		ut := &design.UserTypeDefinition{
+			TypeName:            "Status",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+		}
+		Ω(codegen.GoDescriptionMethod(ut)).Should(Equal(statusDescriptionCode))
+	})
+})
+
+const bottleDescriptionCode = `// Description returns the description of the Bottle type.
+func (*Bottle) Description() string {
+	return "A bottle of \"wine\""
+}
+
+// FieldDescription returns the description of the Bottle attribute with the given name, the
+// empty string if there is no such attribute or if it has no description.
+func (*Bottle) FieldDescription(name string) string {
+	switch name {
+	case "name":
+		return "Name of bottle"
+	}
+	return ""
+}
+`
+
+const statusDescriptionCode = `// Description returns the description of the Status type.
+func (Status) Description() string {
+	return ""
+}
+`
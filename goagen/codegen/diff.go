@@ -0,0 +1,124 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// diffRecurseKey is the name of the metadata that requests that the changes of the fields of an
+// object user type attribute be reported individually by GoDiff.
+const diffRecurseKey = "struct:diff:recurse"
+
+var diffT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if diffT, err = template.New("diff").Parse(diffTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoFieldChangeTypeDef returns the Go code that defines the FieldChange type returned by the
+// functions generated with GoDiff. The code must be generated once per package.
+func GoFieldChangeTypeDef() string {
+	return fieldChangeTypeCode
+}
+
+// GoDiff produces the Go code of a function that lists the fields whose values differ between two
+// instances of the given object user type, e.g. `DiffBottle(old, new *Bottle) []FieldChange`. The
+// changes are reported in the order of the attribute names and use the names of the attributes
+// as field names, nil instances are compared as instances whose fields are not set. Pointer
+// fields are compared by value, slices of comparable values element by element and the other
+// arrays, hashes and objects as a whole with reflect.DeepEqual. Fields whose type is an object
+// user type that defines the "struct:diff:recurse" metadata are compared field by field with the
+// Diff function generated for that type instead, the names of their changes are prefixed with the
+// name of the attribute, e.g. "label.text". The generated code requires the "reflect" package.
+func GoDiff(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: Diff requires an object user type")
+	}
+	att := ut.AttributeDefinition
+	var buf bytes.Buffer
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		field := goFieldName(n, catt)
+		o, v := "old."+field, "new."+field
+		record := fmt.Sprintf("changes = append(changes, FieldChange{Field: %q, Old: %s, New: %s})", n, o, v)
+		if _, ok := catt.Metadata[diffRecurseKey]; ok {
+			if !isObjectUserType(catt.Type) {
+				panic(fmt.Sprintf("%s metadata of attribute %#v of %s requires an object user type", diffRecurseKey, n, ut.TypeName))
+			}
+			writeLine(&buf, 1, "for _, c := range Diff%s(%s, %s) {", GoTypeName(catt.Type, nil, 0, false), o, v)
+			writeLine(&buf, 2, "c.Field = %q + c.Field", n+".")
+			writeLine(&buf, 2, "changes = append(changes, c)")
+			writeLine(&buf, 1, "}")
+			return nil
+		}
+		if catt.Type.IsArray() && isDiffComparable(catt.Type.ToArray().ElemType.Type) {
+			writeLine(&buf, 1, "if len(%s) != len(%s) {", o, v)
+			writeLine(&buf, 2, "%s", record)
+			writeLine(&buf, 1, "} else {")
+			writeLine(&buf, 2, "for i := range %s {", o)
+			writeLine(&buf, 3, "if %s[i] != %s[i] {", o, v)
+			writeLine(&buf, 4, "%s", record)
+			writeLine(&buf, 4, "break")
+			writeLine(&buf, 3, "}")
+			writeLine(&buf, 2, "}")
+			writeLine(&buf, 1, "}")
+			return nil
+		}
+		var changed string
+		switch {
+		case catt.Type.Kind() == design.BigIntKind:
+			changed = fmt.Sprintf("(%s == nil) != (%s == nil) || %s != nil && %s.Cmp(%s) != 0", o, v, o, o, v)
+		case isOptionalPrimitive(att, n, false):
+			changed = fmt.Sprintf("(%s == nil) != (%s == nil) || %s != nil && *%s != *%s", o, v, o, o, v)
+		case isDiffComparable(catt.Type):
+			changed = fmt.Sprintf("%s != %s", o, v)
+		default:
+			changed = fmt.Sprintf("!reflect.DeepEqual(%s, %s)", o, v)
+		}
+		writeLine(&buf, 1, "if %s {", changed)
+		writeLine(&buf, 2, "%s", record)
+		writeLine(&buf, 1, "}")
+		return nil
+	})
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": buf.String(),
+	}
+	return RunTemplate(diffT, data)
+}
+
+// isDiffComparable returns true if the values of dt may be compared with the == operator in the
+// code generated by GoDiff, that is if they are comparable primitive values.
+func isDiffComparable(dt design.DataType) bool {
+	return dt.IsPrimitive() && IsComparable(dt)
+}
+
+const fieldChangeTypeCode = `// FieldChange describes a field whose value differs between two instances of a type.
+type FieldChange struct {
+	// Field is the name of the attribute of the field.
+	Field string
+	// Old is the value of the field in the old instance.
+	Old interface{}
+	// New is the value of the field in the new instance.
+	New interface{}
+}
+`
+
+const diffTmpl = `// Diff{{ .Name }} returns the changes of the fields of the {{ .Name }} from old to new.
+func Diff{{ .Name }}(old, new *{{ .Name }}) []FieldChange {
+	if old == nil {
+		old = &{{ .Name }}{}
+	}
+	if new == nil {
+		new = &{{ .Name }}{}
+	}
+	var changes []FieldChange
+{{ .Fields }}	return changes
+}
+`
@@ -0,0 +1,86 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDiff", func() {
+	var label, ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		label = &design.UserTypeDefinition{
+			TypeName: "Label",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+				"text": &design.AttributeDefinition{Type: design.String},
+			}},
+		}
+		ut = &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":     &design.AttributeDefinition{Type: design.Integer},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					"label":  &design.AttributeDefinition{Type: label},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+			},
+		}
+	})
+
+	It("compares each field", func() {
+		Ω(codegen.GoDiff(ut)).Should(Equal(diffBottleCode))
+	})
+
+	It("recurses into user types that request it", func() {
+		ut.Type.ToObject()["label"].Metadata = dslengine.MetadataDefinition{"struct:diff:recurse": nil}
+		Ω(codegen.GoDiff(ut)).Should(ContainSubstring("\tfor _, c := range DiffLabel(old.Label, new.Label) {\n" +
+			"\t\tc.Field = \"label.\" + c.Field\n" +
+			"\t\tchanges = append(changes, c)\n" +
+			"\t}\n"))
+	})
+
+	It("panics if inline objects request recursion", func() {
+		ut.Type.ToObject()["label"] = &design.AttributeDefinition{
+			Type:     design.Object{},
+			Metadata: dslengine.MetadataDefinition{"struct:diff:recurse": nil},
+		}
+		Ω(func() { codegen.GoDiff(ut) }).Should(Panic())
+	})
+})
+
+const diffBottleCode = `// DiffBottle returns the changes of the fields of the Bottle from old to new.
+func DiffBottle(old, new *Bottle) []FieldChange {
+	if old == nil {
+		old = &Bottle{}
+	}
+	if new == nil {
+		new = &Bottle{}
+	}
+	var changes []FieldChange
+	if old.ID != new.ID {
+		changes = append(changes, FieldChange{Field: "id", Old: old.ID, New: new.ID})
+	}
+	if !reflect.DeepEqual(old.Label, new.Label) {
+		changes = append(changes, FieldChange{Field: "label", Old: old.Label, New: new.Label})
+	}
+	if (old.Rating == nil) != (new.Rating == nil) || old.Rating != nil && *old.Rating != *new.Rating {
+		changes = append(changes, FieldChange{Field: "rating", Old: old.Rating, New: new.Rating})
+	}
+	if len(old.Tags) != len(new.Tags) {
+		changes = append(changes, FieldChange{Field: "tags", Old: old.Tags, New: new.Tags})
+	} else {
+		for i := range old.Tags {
+			if old.Tags[i] != new.Tags[i] {
+				changes = append(changes, FieldChange{Field: "tags", Old: old.Tags, New: new.Tags})
+				break
+			}
+		}
+	}
+	return changes
+}
+`
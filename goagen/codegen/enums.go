@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var enumConstantsT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if enumConstantsT, err = template.New("enumConstants").Parse(enumConstantsTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// enumConstants describes the constants generated for the values of an enum user type.
+type enumConstants struct {
+	// TypeName is the name of the Go type of the constants.
+	TypeName string
+	// Values lists the constants in the order of the enum values.
+	Values []*enumConstant
+}
+
+// enumConstant describes the constant generated for an enum value.
+type enumConstant struct {
+	// Name is the name of the constant.
+	Name string
+	// Literal is the Go literal of the value.
+	Literal string
+}
+
+// EnumUserType returns the user type that defines the enum used by the given data type if any,
+// nil otherwise. An enum user type is a named primitive type with an enum validation. Attributes
+// that use an enum user type all share the constants generated by GoEnumConstants.
+func EnumUserType(dt design.DataType) *design.UserTypeDefinition {
+	var ut *design.UserTypeDefinition
+	switch actual := dt.(type) {
+	case *design.UserTypeDefinition:
+		ut = actual
+	case *design.MediaTypeDefinition:
+		if actual != nil {
+			ut = actual.UserTypeDefinition
+		}
+	default:
+		return nil
+	}
+	if ut == nil || ut.AttributeDefinition == nil || ut.Type == nil {
+		return nil
+	}
+	if !ut.Type.IsPrimitive() || ut.Validation == nil || len(ut.Validation.Values) == 0 {
+		return nil
+	}
+	return ut
+}
+
+// GoEnumConstants produces the Go code that declares one constant for each value of the given
+// enum user types. Types that are not enum user types are ignored and each type is generated
+// only once even if it appears multiple times in types so that the result can be computed from
+// the types used by all the attributes of a design. The constants are named after the type and
+// the Goified value, e.g. "StatusActive". Values that produce the same name, e.g. "a-b" and "a_b",
+// get the name suffixed with a counter in the order of the values (e.g. "TypeAB" and "TypeAB2").
+func GoEnumConstants(types ...*design.UserTypeDefinition) string {
+	seen := make(map[string]*design.UserTypeDefinition)
+	var names []string
+	for _, ut := range types {
+		if EnumUserType(ut) == nil {
+			continue
+		}
+		if _, ok := seen[ut.TypeName]; ok {
+			continue
+		}
+		seen[ut.TypeName] = ut
+		names = append(names, ut.TypeName)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	enums := make([]*enumConstants, len(names))
+	for i, n := range names {
+		enums[i] = newEnumConstants(seen[n])
+	}
+	return RunTemplate(enumConstantsT, enums)
+}
+
+// newEnumConstants builds the description of the constants of the given enum user type.
+func newEnumConstants(ut *design.UserTypeDefinition) *enumConstants {
+	prefix := Goify(ut.TypeName, true)
+	taken := make(map[string]bool)
+	values := make([]*enumConstant, len(ut.Validation.Values))
+	for i, v := range ut.Validation.Values {
+		values[i] = &enumConstant{
+			Name:    uniqueName(prefix+Goify(fmt.Sprintf("%v", v), true), taken),
+			Literal: fmt.Sprintf("%#v", v),
+		}
+	}
+	return &enumConstants{TypeName: GoTypeName(ut, nil, 0, false), Values: values}
+}
+
+const enumConstantsTmpl = `const (
+{{ range $i, $enum := . }}{{ if $i }}
+{{ end }}	// Enum values of {{ $enum.TypeName }}
+{{ range $enum.Values }}	{{ .Name }} {{ $enum.TypeName }} = {{ .Literal }}
+{{ end }}{{ end }})
+`
@@ -0,0 +1,98 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoEnumConstants", func() {
+	var types []*design.UserTypeDefinition
+	var code string
+
+	JustBeforeEach(func() {
+		code = codegen.GoEnumConstants(types...)
+	})
+
+	Context("given enum user types used by multiple attributes", func() {
+		BeforeEach(func() {
+			status := &design.UserTypeDefinition{
+				TypeName: "status",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{"active", "inactive"}},
+				},
+			}
+			level := &design.UserTypeDefinition{
+				TypeName: "level",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       design.Integer,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{1, 2}},
+				},
+			}
+			object := &design.UserTypeDefinition{
+				TypeName:            "bottle",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+			}
+			types = []*design.UserTypeDefinition{status, level, object, status}
+		})
+
+		It("generates the constants once per type", func() {
+			Ω(code).Should(Equal(enumConstantsCode))
+		})
+	})
+
+	Context("given enum values that produce the same constant name", func() {
+		BeforeEach(func() {
+			types = []*design.UserTypeDefinition{{
+				TypeName: "kind",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Values: []interface{}{"a-b", "a_b"}},
+				},
+			}}
+		})
+
+		It("generates distinct constants", func() {
+			Ω(code).Should(ContainSubstring("KindAB Kind = \"a-b\"\n"))
+			Ω(code).Should(ContainSubstring("KindAB2 Kind = \"a_b\"\n"))
+		})
+	})
+
+	Context("given no enum user type", func() {
+		BeforeEach(func() {
+			types = []*design.UserTypeDefinition{{
+				TypeName:            "name",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+			}}
+		})
+
+		It("generates nothing", func() {
+			Ω(code).Should(BeEmpty())
+		})
+	})
+
+	Context("given a nil user type", func() {
+		BeforeEach(func() {
+			types = []*design.UserTypeDefinition{nil}
+		})
+
+		It("generates nothing", func() {
+			Ω(code).Should(BeEmpty())
+			Ω(codegen.EnumUserType(&design.MediaTypeDefinition{})).Should(BeNil())
+		})
+	})
+})
+
+const enumConstantsCode = `const (
+	// Enum values of Level
+	Level1 Level = 1
+	Level2 Level = 2
+
+	// Enum values of Status
+	StatusActive Status = "active"
+	StatusInactive Status = "inactive"
+)
+`
@@ -0,0 +1,49 @@
+package codegen
+
+import "github.com/goadesign/goa/design"
+
+// AttributesEqualGo returns true if the given attributes produce identical Go struct fields, that
+// is fields with the same name, type and tags when generated under the same attribute name. The
+// comparison is done on the generated code rather than on the design so that attributes that
+// only differ in ways that do not show in Go are considered equal, e.g. attributes whose
+// descriptions, examples or validations other than the required attributes differ. It makes it
+// possible to deduplicate the types generated for several services.
+func AttributesEqualGo(a, b *design.AttributeDefinition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return goFieldDef(a) == goFieldDef(b)
+}
+
+// goFieldDef returns the Go code of the struct field generated for att.
+func goFieldDef(att *design.AttributeDefinition) string {
+	parent := &design.AttributeDefinition{Type: design.Object{"field": withoutDocs(att)}}
+	return GoTypeDef(parent, 0, true, false)
+}
+
+// withoutDocs returns a copy of att without the descriptions which only produce comments.
+func withoutDocs(att *design.AttributeDefinition) *design.AttributeDefinition {
+	dup := design.DupAtt(att)
+	clearDescriptions(dup)
+	return dup
+}
+
+// clearDescriptions removes the descriptions of att and of the attributes of the inline types it
+// contains, user types are generated by name and thus not visited.
+func clearDescriptions(att *design.AttributeDefinition) {
+	if att == nil {
+		return
+	}
+	att.Description = ""
+	switch actual := att.Type.(type) {
+	case *design.Array:
+		clearDescriptions(actual.ElemType)
+	case *design.Hash:
+		clearDescriptions(actual.KeyType)
+		clearDescriptions(actual.ElemType)
+	case design.Object:
+		for _, catt := range actual {
+			clearDescriptions(catt)
+		}
+	}
+}
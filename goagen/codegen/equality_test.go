@@ -0,0 +1,52 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttributesEqualGo", func() {
+	var a, b *design.AttributeDefinition
+
+	BeforeEach(func() {
+		a = &design.AttributeDefinition{
+			Type: design.Object{
+				"name":   &design.AttributeDefinition{Type: design.String, Description: "Name"},
+				"rating": &design.AttributeDefinition{Type: design.Integer},
+			},
+			Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+		}
+		b = design.DupAtt(a)
+	})
+
+	It("ignores descriptions, examples and validations", func() {
+		b.Type.ToObject()["name"].Description = "The name"
+		max := 5.0
+		b.Type.ToObject()["rating"].Validation = &dslengine.ValidationDefinition{Maximum: &max}
+		b.Type.ToObject()["rating"].Example = 3
+		Ω(codegen.AttributesEqualGo(a, b)).Should(BeTrue())
+	})
+
+	It("detects different requiredness", func() {
+		b.Validation.Required = nil
+		Ω(codegen.AttributesEqualGo(a, b)).Should(BeFalse())
+	})
+
+	It("detects different types", func() {
+		b.Type.ToObject()["rating"].Type = design.Number
+		Ω(codegen.AttributesEqualGo(a, b)).Should(BeFalse())
+	})
+
+	It("detects different tags", func() {
+		b.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{"struct:tag:json": {"title"}}
+		Ω(codegen.AttributesEqualGo(a, b)).Should(BeFalse())
+	})
+
+	It("leaves the attributes unchanged", func() {
+		codegen.AttributesEqualGo(a, b)
+		Ω(a.Type.ToObject()["name"].Description).Should(Equal("Name"))
+	})
+})
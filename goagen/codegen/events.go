@@ -0,0 +1,83 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// eventNameKey is the name of the metadata that overrides the name of an event type.
+const eventNameKey = "struct:event:name"
+
+var eventRegistryT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if eventRegistryT, err = template.New("eventRegistry").Parse(eventRegistryTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoEventRegistry produces the Go code that declares the EventType enum listing the given user
+// types, the EventRegistry map that maps each event type to a function that allocates a value of
+// the corresponding Go type and the DecodeEvent function that decodes JSON encoded events.
+// The name of an event type is the value of the "struct:event:name" metadata of the user type if
+// any, the name of the generated Go type otherwise. GoEventRegistry panics if two user types
+// have the same event name or if their event names produce the same constant name.
+// The generated code requires the "encoding/json" and "fmt" packages.
+func GoEventRegistry(types []*design.UserTypeDefinition) string {
+	type event struct {
+		Const, Name, TypeName string
+	}
+	events := make([]*event, len(types))
+	names := make(map[string]string, len(types))
+	consts := make(map[string]string, len(types))
+	for i, ut := range types {
+		typeName := GoTypeName(ut, nil, 0, false)
+		name := typeName
+		if val, ok := ut.Metadata[eventNameKey]; ok && len(val) > 0 {
+			name = val[0]
+		}
+		if other, ok := names[name]; ok {
+			panic(fmt.Sprintf("event types %s and %s have the same event name %#v, use the %s metadata to rename one of them",
+				other, ut.TypeName, name, eventNameKey))
+		}
+		constName := "EventType" + Goify(name, true)
+		if other, ok := consts[constName]; ok {
+			panic(fmt.Sprintf("the event names of types %s and %s produce the same constant %s, use the %s metadata to rename one of them",
+				other, ut.TypeName, constName, eventNameKey))
+		}
+		names[name], consts[constName] = ut.TypeName, ut.TypeName
+		events[i] = &event{Const: constName, Name: name, TypeName: typeName}
+	}
+	return RunTemplate(eventRegistryT, events)
+}
+
+const eventRegistryTmpl = `// EventType enumerates the types of events.
+type EventType string
+
+const (
+{{ range . }}	// {{ .Const }} is the type of the {{ .TypeName }} events.
+	{{ .Const }} EventType = {{ printf "%q" .Name }}
+{{ end }})
+
+// EventRegistry maps the event types to functions that allocate the event values.
+var EventRegistry = map[EventType]func() interface{}{
+{{ range . }}	{{ .Const }}: func() interface{} { return new({{ .TypeName }}) },
+{{ end }}}
+
+// DecodeEvent decodes the JSON encoded event of the given type.
+func DecodeEvent(t EventType, data []byte) (interface{}, error) {
+	alloc, ok := EventRegistry[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", t)
+	}
+	v := alloc()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+`
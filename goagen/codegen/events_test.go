@@ -0,0 +1,68 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoEventRegistry", func() {
+	var created, deleted *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		created = &design.UserTypeDefinition{
+			TypeName: "BottleCreated",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"id": &design.AttributeDefinition{Type: design.Integer}},
+				Metadata: dslengine.MetadataDefinition{"struct:event:name": {"bottle.created"}},
+			},
+		}
+		deleted = &design.UserTypeDefinition{
+			TypeName:            "BottleDeleted",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Integer},
+		}
+	})
+
+	It("produces the enum, the registry and the decode function", func() {
+		Ω(codegen.GoEventRegistry([]*design.UserTypeDefinition{created, deleted})).Should(Equal(eventRegistryCode))
+	})
+
+	It("rejects duplicate event names", func() {
+		deleted.Metadata = dslengine.MetadataDefinition{"struct:event:name": {"bottle.created"}}
+		Ω(func() { codegen.GoEventRegistry([]*design.UserTypeDefinition{created, deleted}) }).Should(Panic())
+		deleted.Metadata = dslengine.MetadataDefinition{"struct:event:name": {"bottle_created"}}
+		Ω(func() { codegen.GoEventRegistry([]*design.UserTypeDefinition{created, deleted}) }).Should(Panic())
+	})
+})
+
+const eventRegistryCode = `// EventType enumerates the types of events.
+type EventType string
+
+const (
+	// EventTypeBottleCreated is the type of the BottleCreated events.
+	EventTypeBottleCreated EventType = "bottle.created"
+	// EventTypeBottleDeleted is the type of the BottleDeleted events.
+	EventTypeBottleDeleted EventType = "BottleDeleted"
+)
+
+// EventRegistry maps the event types to functions that allocate the event values.
+var EventRegistry = map[EventType]func() interface{}{
+	EventTypeBottleCreated: func() interface{} { return new(BottleCreated) },
+	EventTypeBottleDeleted: func() interface{} { return new(BottleDeleted) },
+}
+
+// DecodeEvent decodes the JSON encoded event of the given type.
+func DecodeEvent(t EventType, data []byte) (interface{}, error) {
+	alloc, ok := EventRegistry[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", t)
+	}
+	v := alloc()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+`
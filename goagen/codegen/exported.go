@@ -0,0 +1,42 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// CheckAllExported returns an error if the user type defines the "struct:binary" metadata and any
+// of its attributes, including the attributes of inline objects, produces a struct field that is
+// not exported. Binary serializers such as gob ignore unexported fields so that such fields would
+// silently be dropped. A field may not be exported if the attribute name starts with a digit once
+// cleaned up by Goify, the "struct:field:name" metadata can be used to rename it.
+func CheckAllExported(ut *design.UserTypeDefinition) error {
+	if _, ok := ut.Metadata["struct:binary"]; !ok || !ut.IsObject() {
+		return nil
+	}
+	var unexported []string
+	checkExported(ut.AttributeDefinition, "", &unexported)
+	if len(unexported) == 0 {
+		return nil
+	}
+	return fmt.Errorf("type %s is serialized in binary form but the following attributes do not produce exported fields: %s",
+		ut.TypeName, strings.Join(unexported, ", "))
+}
+
+// checkExported appends the paths of the attributes of att that produce unexported fields to
+// unexported, prefix is the path of att.
+func checkExported(att *design.AttributeDefinition, prefix string, unexported *[]string) {
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		path := prefix + n
+		if field := goFieldName(n, catt); !ast.IsExported(field) {
+			*unexported = append(*unexported, fmt.Sprintf("%#v (field %#v)", path, field))
+		}
+		if _, ok := catt.Type.(design.Object); ok {
+			checkExported(catt, path+".", unexported)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckAllExported", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"1st":  &design.AttributeDefinition{Type: design.Integer},
+					"info": &design.AttributeDefinition{Type: design.Object{
+						"2nd": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				Metadata: dslengine.MetadataDefinition{"struct:binary": nil},
+			},
+		}
+	})
+
+	It("reports the attributes that produce unexported fields", func() {
+		err := codegen.CheckAllExported(ut)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(`type bottle is serialized in binary form but the following attributes do not produce exported fields: "1st" (field "1st"), "info.2nd" (field "2nd")`))
+	})
+
+	It("accepts attributes renamed with struct:field:name", func() {
+		ut.Type.ToObject()["1st"].Metadata = dslengine.MetadataDefinition{"struct:field:name": {"First"}}
+		ut.Type.ToObject()["info"].Type.ToObject()["2nd"].Metadata = dslengine.MetadataDefinition{"struct:field:name": {"Second"}}
+		Ω(codegen.CheckAllExported(ut)).Should(Succeed())
+	})
+
+	It("ignores types that are not serialized in binary form", func() {
+		ut.Metadata = nil
+		Ω(codegen.CheckAllExported(ut)).Should(Succeed())
+	})
+})
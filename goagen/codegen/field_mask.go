@@ -0,0 +1,146 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fieldMaskT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if fieldMaskT, err = template.New("fieldMask").Parse(fieldMaskTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// maskPath describes a field path of the generated mask.
+type maskPath struct {
+	// Const is the name of the constant holding the path.
+	Const string
+	// Path is the dotted path of the field, e.g. "address.zip".
+	Path string
+}
+
+// GoFieldMask produces the Go code that declares the field mask of the given object user type:
+// a string type whose constants enumerate the dotted paths of the fields of the type and of the
+// nested objects, e.g. `BottleMaskAddressZip BottleMaskPath = "address.zip"`, a set of such paths,
+// e.g. "BottleMask", and its Apply method which copies the fields of a patch listed in the mask
+// onto a base value. The fields of nested objects are listed recursively except for the user
+// types that contain themselves which are only listed once, arrays and hashes are copied
+// wholesale. GoFieldMask panics if two paths produce the same constant name.
+func GoFieldMask(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: field masks require an object user type")
+	}
+	name := GoTypeName(ut, nil, 0, false)
+	w := &maskWriter{
+		prefix: name + "Mask",
+		taken:  make(map[string]string),
+		seen:   map[string]bool{ut.TypeName: true},
+	}
+	w.writeFields(ut.AttributeDefinition, "base", "patch", "", "", nil, 1)
+	data := map[string]interface{}{
+		"Name":  name,
+		"Paths": w.paths,
+		"Apply": w.buf.String(),
+	}
+	return RunTemplate(fieldMaskT, data)
+}
+
+// maskWriter accumulates the paths and the Apply code of a field mask.
+type maskWriter struct {
+	// prefix is the prefix of the constant names.
+	prefix string
+	// paths lists the field paths in depth first order.
+	paths []*maskPath
+	// taken maps the constant names to their paths to detect collisions.
+	taken map[string]string
+	// seen records the user types being visited to break cycles.
+	seen map[string]bool
+	// buf holds the code of the Apply method.
+	buf bytes.Buffer
+}
+
+// maskAlloc describes a nested object of the base value that must be allocated before one of its
+// fields is set.
+type maskAlloc struct {
+	// Field is the expression of the struct field holding the object.
+	Field string
+	// Att is the attribute of the object.
+	Att *design.AttributeDefinition
+}
+
+// writeFields records the paths of the fields of the object att and writes the code that copies
+// the fields of pvar listed in the mask onto bvar. allocs lists the objects of bvar that contain
+// the fields, outermost first.
+func (w *maskWriter) writeFields(att *design.AttributeDefinition, bvar, pvar, path, suffix string, allocs []*maskAlloc, depth int) {
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		field := goFieldName(n, catt)
+		fpath, fsuffix := path+n, suffix+field
+		c := w.prefix + fsuffix
+		if other, ok := w.taken[c]; ok {
+			panic(fmt.Sprintf("field paths %#v and %#v produce the same constant name %s", other, fpath, c))
+		}
+		w.taken[c] = fpath
+		w.paths = append(w.paths, &maskPath{Const: c, Path: fpath})
+		bfield, pfield := bvar+"."+field, pvar+"."+field
+		writeLine(&w.buf, depth, "if mask[%s] {", c)
+		for _, a := range allocs {
+			typ := GoTypeName(a.Att.Type, nil, 0, false)
+			if !isUserType(a.Att.Type) {
+				typ = GoTypeDef(a.Att, depth+2, true, false)
+			}
+			writeLine(&w.buf, depth+1, "if %s == nil {", a.Field)
+			writeLine(&w.buf, depth+2, "%s = new(%s)", a.Field, typ)
+			writeLine(&w.buf, depth+1, "}")
+		}
+		writeLine(&w.buf, depth+1, "%s = %s", bfield, pfield)
+		if w.hasNestedPaths(catt) {
+			if ut := userTypeName(catt.Type); ut != "" {
+				w.seen[ut] = true
+				defer delete(w.seen, ut)
+			}
+			nested := append(allocs[:len(allocs):len(allocs)], &maskAlloc{Field: bfield, Att: catt})
+			writeLine(&w.buf, depth, "} else if %s != nil {", pfield)
+			w.writeFields(underlyingAttribute(catt), bfield, pfield, fpath+".", fsuffix, nested, depth+1)
+		}
+		writeLine(&w.buf, depth, "}")
+		return nil
+	})
+}
+
+// hasNestedPaths returns true if att is a nested object whose fields have paths of their own,
+// that is a non empty object that is not a user type being visited.
+func (w *maskWriter) hasNestedPaths(att *design.AttributeDefinition) bool {
+	if !att.Type.IsObject() || len(att.Type.ToObject()) == 0 {
+		return false
+	}
+	return !w.seen[userTypeName(att.Type)]
+}
+
+const fieldMaskTmpl = `{{ $name := .Name }}// {{ $name }}MaskPath is the path of a field of a {{ $name }}, the names of the fields of
+// nested objects are separated with dots.
+type {{ $name }}MaskPath string
+
+// Paths of the fields of a {{ $name }}.
+const (
+{{ range .Paths }}	{{ .Const }} {{ $name }}MaskPath = {{ printf "%q" .Path }}
+{{ end }})
+
+// {{ $name }}Mask is a set of paths of the fields of a {{ $name }}.
+type {{ $name }}Mask map[{{ $name }}MaskPath]bool
+
+// Apply copies the fields of patch whose paths are in the mask onto base. The fields of the
+// nested objects of patch are copied individually unless the path of the object is in the mask,
+// the nested objects of base are allocated as needed.
+func (mask {{ $name }}Mask) Apply(base, patch *{{ $name }}) {
+	if base == nil || patch == nil {
+		return
+	}
+{{ .Apply }}}
+`
@@ -0,0 +1,81 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFieldMask", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		address := &design.UserTypeDefinition{
+			TypeName: "Address",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+				"zip": &design.AttributeDefinition{Type: design.String},
+			}},
+		}
+		ut = &design.UserTypeDefinition{
+			TypeName: "Account",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+				"address": &design.AttributeDefinition{Type: address},
+				"name":    &design.AttributeDefinition{Type: design.String},
+			}},
+		}
+	})
+
+	It("produces the field mask and its Apply method", func() {
+		Ω(codegen.GoFieldMask(ut)).Should(Equal(accountMaskCode))
+	})
+
+	It("lists recursive types once", func() {
+		ut.Type.ToObject()["parent"] = &design.AttributeDefinition{Type: ut}
+		code := codegen.GoFieldMask(ut)
+		Ω(code).Should(ContainSubstring("\tAccountMaskParent AccountMaskPath = \"parent\"\n"))
+		Ω(code).ShouldNot(ContainSubstring("AccountMaskParentName"))
+	})
+
+	It("rejects paths that produce the same constant name", func() {
+		ut.Type.ToObject()["address_zip"] = &design.AttributeDefinition{Type: design.String}
+		Ω(func() { codegen.GoFieldMask(ut) }).Should(Panic())
+	})
+})
+
+const accountMaskCode = `// AccountMaskPath is the path of a field of a Account, the names of the fields of
+// nested objects are separated with dots.
+type AccountMaskPath string
+
+// Paths of the fields of a Account.
+const (
+	AccountMaskAddress AccountMaskPath = "address"
+	AccountMaskAddressZip AccountMaskPath = "address.zip"
+	AccountMaskName AccountMaskPath = "name"
+)
+
+// AccountMask is a set of paths of the fields of a Account.
+type AccountMask map[AccountMaskPath]bool
+
+// Apply copies the fields of patch whose paths are in the mask onto base. The fields of the
+// nested objects of patch are copied individually unless the path of the object is in the mask,
+// the nested objects of base are allocated as needed.
+func (mask AccountMask) Apply(base, patch *Account) {
+	if base == nil || patch == nil {
+		return
+	}
+	if mask[AccountMaskAddress] {
+		base.Address = patch.Address
+	} else if patch.Address != nil {
+		if mask[AccountMaskAddressZip] {
+			if base.Address == nil {
+				base.Address = new(Address)
+			}
+			base.Address.Zip = patch.Address.Zip
+		}
+	}
+	if mask[AccountMaskName] {
+		base.Name = patch.Name
+	}
+}
+`
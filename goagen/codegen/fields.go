@@ -0,0 +1,67 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fieldsMethodT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if fieldsMethodT, err = template.New("fieldsMethod").Parse(fieldsMethodTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// fieldsMethodField describes a field listed by the generated Fields method.
+type fieldsMethodField struct {
+	// Name is the name of the attribute.
+	Name string
+	// Field is the name of the struct field.
+	Field string
+	// Nilable is true if the field is a pointer, a slice or a map.
+	Nilable bool
+}
+
+// GoFieldsMethod produces the Go code of the Fields method of the given object user type. The
+// method returns the names of the attributes whose fields are set: fields that are pointers,
+// slices or maps are set if not nil and the other fields are always set. The names are the
+// attribute names as defined in the design. They are listed in alphabetical order and NOT in
+// declaration order: design objects are maps that do not record the order in which attributes
+// are declared. The alphabetical order is the same on every run so that logs remain stable.
+func GoFieldsMethod(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: Fields method requires an object user type")
+	}
+	att := ut.AttributeDefinition
+	var fields []*fieldsMethodField
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		nilable := catt.Type.IsObject() || catt.Type.IsArray() || catt.Type.IsHash() ||
+			isNilablePrimitive(catt.Type) || att.IsPrimitivePointer(n)
+		fields = append(fields, &fieldsMethodField{
+			Name:    n,
+			Field:   goFieldName(n, catt),
+			Nilable: nilable,
+		})
+		return nil
+	})
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": fields,
+	}
+	return RunTemplate(fieldsMethodT, data)
+}
+
+const fieldsMethodTmpl = `// Fields returns the names of the {{ .Name }} fields that are set.
+func (ut *{{ .Name }}) Fields() []string {
+	fields := make([]string, 0, {{ len .Fields }})
+{{ range .Fields }}{{ if .Nilable }}	if ut.{{ .Field }} != nil {
+		fields = append(fields, {{ printf "%q" .Name }})
+	}
+{{ else }}	fields = append(fields, {{ printf "%q" .Name }})
+{{ end }}{{ end }}	return fields
+}
+`
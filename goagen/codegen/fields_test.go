@@ -0,0 +1,61 @@
+package codegen_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFieldsMethod", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":      &design.AttributeDefinition{Type: design.String},
+					"rating":    &design.AttributeDefinition{Type: design.Integer},
+					"vineyards": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	It("checks the nilable fields", func() {
+		Ω(codegen.GoFieldsMethod(ut)).Should(Equal(fieldsMethodCode))
+	})
+
+	It("lists the names in alphabetical order rather than in declaration order", func() {
+		obj := ut.Type.ToObject()
+		obj["vintage"] = &design.AttributeDefinition{Type: design.Integer}
+		obj["color"] = &design.AttributeDefinition{Type: design.String}
+		code := codegen.GoFieldsMethod(ut)
+		var last int
+		for _, n := range []string{"color", "name", "rating", "vineyards", "vintage"} {
+			i := strings.Index(code, fmt.Sprintf("%q", n))
+			Ω(i).Should(BeNumerically(">", last), n)
+			last = i
+		}
+	})
+})
+
+const fieldsMethodCode = `// Fields returns the names of the Bottle fields that are set.
+func (ut *Bottle) Fields() []string {
+	fields := make([]string, 0, 3)
+	fields = append(fields, "name")
+	if ut.Rating != nil {
+		fields = append(fields, "rating")
+	}
+	if ut.Vineyards != nil {
+		fields = append(fields, "vineyards")
+	}
+	return fields
+}
+`
@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fixedStringT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if fixedStringT, err = template.New("fixedString").Parse(fixedStringTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// FixedStringSize returns the size of the byte array that can hold the values of the given
+// attribute, that is its maximum length if it is a string with a MaxLength validation. It returns
+// false if the values of the attribute cannot be stored in a fixed size array.
+func FixedStringSize(att *design.AttributeDefinition) (int, bool) {
+	if att.Type.Kind() != design.StringKind || att.Validation == nil || att.Validation.MaxLength == nil {
+		return 0, false
+	}
+	if n := *att.Validation.MaxLength; n > 0 {
+		return n, true
+	}
+	return 0, false
+}
+
+// GoFixedString produces the Go code of a type that stores the values of the given string user
+// type in a byte array sized after its MaxLength validation (see FixedStringSize) rather than in a
+// string to avoid allocations in hot decoding paths. The type is a struct holding the array and
+// the length of the value with the Set, String and Bytes accessors and the MarshalText and
+// UnmarshalText methods so that it can be encoded as a string. As with the length validations
+// the maximum length is a number of bytes. The generated code requires the "fmt" package.
+func GoFixedString(ut *design.UserTypeDefinition) string {
+	size, ok := FixedStringSize(ut.AttributeDefinition)
+	if !ok {
+		panic("goa bug: fixed strings require a string user type with a maximum length")
+	}
+	lenType := "int"
+	switch {
+	case size <= 0xff:
+		lenType = "uint8"
+	case size <= 0xffff:
+		lenType = "uint16"
+	}
+	data := map[string]interface{}{
+		"Name":    GoTypeName(ut, nil, 0, false),
+		"Size":    size,
+		"LenType": lenType,
+	}
+	return RunTemplate(fixedStringT, data)
+}
+
+const fixedStringTmpl = `// {{ .Name }} holds a string of at most {{ .Size }} bytes in a fixed size array.
+type {{ .Name }} struct {
+	buf [{{ .Size }}]byte
+	n   {{ .LenType }}
+}
+
+// New{{ .Name }} returns the {{ .Name }} holding s, it returns an error if s is longer than {{ .Size }} bytes.
+func New{{ .Name }}(s string) ({{ .Name }}, error) {
+	var v {{ .Name }}
+	err := v.Set(s)
+	return v, err
+}
+
+// Set sets the value to s, it returns an error and leaves the value unchanged if s is longer
+// than {{ .Size }} bytes.
+func (v *{{ .Name }}) Set(s string) error {
+	if len(s) > len(v.buf) {
+		return fmt.Errorf("length of {{ .Name }} value must be at most %d bytes but got %d", len(v.buf), len(s))
+	}
+	v.n = {{ .LenType }}(copy(v.buf[:], s))
+	return nil
+}
+
+// String returns the value as a string.
+func (v {{ .Name }}) String() string {
+	return string(v.buf[:v.n])
+}
+
+// Bytes returns the value, the returned slice shares the array of v.
+func (v *{{ .Name }}) Bytes() []byte {
+	return v.buf[:v.n]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v {{ .Name }}) MarshalText() ([]byte, error) {
+	return append([]byte(nil), v.buf[:v.n]...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler without allocating.
+func (v *{{ .Name }}) UnmarshalText(text []byte) error {
+	if len(text) > len(v.buf) {
+		return fmt.Errorf("length of {{ .Name }} value must be at most %d bytes but got %d", len(v.buf), len(text))
+	}
+	v.n = {{ .LenType }}(copy(v.buf[:], text))
+	return nil
+}
+`
@@ -0,0 +1,102 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFixedString", func() {
+	var maxLength int
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		maxLength = 8
+	})
+
+	JustBeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "code",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:       design.String,
+				Validation: &dslengine.ValidationDefinition{MaxLength: &maxLength},
+			},
+		}
+	})
+
+	It("produces the fixed size type", func() {
+		Ω(codegen.GoFixedString(ut)).Should(Equal(fixedStringCode))
+	})
+
+	Context("with a large maximum length", func() {
+		BeforeEach(func() {
+			maxLength = 1024
+		})
+
+		It("uses a wider length field", func() {
+			Ω(codegen.GoFixedString(ut)).Should(ContainSubstring("buf [1024]byte\n\tn   uint16\n"))
+		})
+	})
+
+	Context("without maximum length", func() {
+		JustBeforeEach(func() {
+			ut.Validation = nil
+		})
+
+		It("panics", func() {
+			_, ok := codegen.FixedStringSize(ut.AttributeDefinition)
+			Ω(ok).Should(BeFalse())
+			Ω(func() { codegen.GoFixedString(ut) }).Should(Panic())
+		})
+	})
+})
+
+const fixedStringCode = `// Code holds a string of at most 8 bytes in a fixed size array.
+type Code struct {
+	buf [8]byte
+	n   uint8
+}
+
+// NewCode returns the Code holding s, it returns an error if s is longer than 8 bytes.
+func NewCode(s string) (Code, error) {
+	var v Code
+	err := v.Set(s)
+	return v, err
+}
+
+// Set sets the value to s, it returns an error and leaves the value unchanged if s is longer
+// than 8 bytes.
+func (v *Code) Set(s string) error {
+	if len(s) > len(v.buf) {
+		return fmt.Errorf("length of Code value must be at most %d bytes but got %d", len(v.buf), len(s))
+	}
+	v.n = uint8(copy(v.buf[:], s))
+	return nil
+}
+
+// String returns the value as a string.
+func (v Code) String() string {
+	return string(v.buf[:v.n])
+}
+
+// Bytes returns the value, the returned slice shares the array of v.
+func (v *Code) Bytes() []byte {
+	return v.buf[:v.n]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Code) MarshalText() ([]byte, error) {
+	return append([]byte(nil), v.buf[:v.n]...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler without allocating.
+func (v *Code) UnmarshalText(text []byte) error {
+	if len(text) > len(v.buf) {
+		return fmt.Errorf("length of Code value must be at most %d bytes but got %d", len(v.buf), len(text))
+	}
+	v.n = uint8(copy(v.buf[:], text))
+	return nil
+}
+`
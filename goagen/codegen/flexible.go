@@ -0,0 +1,99 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var flexibleTypeT *template.Template
+
+// flexibleKinds lists the Go types of the primitive kinds accepted by the "struct:flexible"
+// metadata.
+var flexibleKinds = map[string]string{
+	"boolean": "bool",
+	"integer": "int",
+	"number":  "float64",
+	"string":  "string",
+}
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if flexibleTypeT, err = template.New("flexibleType").Parse(flexibleTypeTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// FlexibleTypeName returns the name of the flexible scalar type generated for the given attribute
+// if the attribute defines the "struct:flexible" metadata, the empty string otherwise. The
+// metadata lists the names of the primitive kinds whose JSON representations are accepted, e.g.
+// "integer" and "string". The name defaults to the Goified kind names joined with "Or" (e.g.
+// "IntegerOrString") and may be overridden with the "struct:field:type" metadata.
+func FlexibleTypeName(att *design.AttributeDefinition) string {
+	kinds, ok := att.Metadata["struct:flexible"]
+	if !ok || len(kinds) == 0 {
+		return ""
+	}
+	if name, ok := att.Metadata["struct:field:type"]; ok && len(name) > 0 {
+		return name[0]
+	}
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = Goify(k, true)
+	}
+	return strings.Join(names, "Or")
+}
+
+// GoFlexibleTypeDef produces the Go code that defines the flexible scalar type of the given
+// attribute, see FlexibleTypeName. The type holds the decoded value and implements
+// json.Unmarshaler by trying each accepted kind in the order listed by the metadata, design
+// validation checks that the kinds are supported. The generated code requires the
+// "encoding/json" and "fmt" packages.
+func GoFlexibleTypeDef(att *design.AttributeDefinition) string {
+	name := FlexibleTypeName(att)
+	if name == "" {
+		panic("goa bug: attribute does not define the struct:flexible metadata")
+	}
+	kinds := att.Metadata["struct:flexible"]
+	types := make([]string, len(kinds))
+	for i, k := range kinds {
+		t, ok := flexibleKinds[k]
+		if !ok {
+			panic(fmt.Sprintf("goa bug: invalid struct:flexible kind %#v", k)) // bug
+		}
+		types[i] = t
+	}
+	data := map[string]interface{}{
+		"Name":  name,
+		"Kinds": strings.Join(kinds, " or "),
+		"Types": types,
+	}
+	return RunTemplate(flexibleTypeT, data)
+}
+
+const flexibleTypeTmpl = `// {{ .Name }} holds a value that may be decoded from a JSON {{ .Kinds }}.
+type {{ .Name }} struct {
+	// Value is the decoded value.
+	Value interface{}
+}
+
+// UnmarshalJSON decodes the data trying each accepted type in order.
+func (f *{{ .Name }}) UnmarshalJSON(data []byte) error {
+{{ range .Types }}	{
+		var v {{ . }}
+		if err := json.Unmarshal(data, &v); err == nil {
+			f.Value = v
+			return nil
+		}
+	}
+{{ end }}	return fmt.Errorf("invalid {{ .Name }} value %s", data)
+}
+
+// MarshalJSON encodes the value.
+func (f {{ .Name }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Value)
+}
+`
@@ -0,0 +1,66 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("flexible types", func() {
+	var att *design.AttributeDefinition
+
+	BeforeEach(func() {
+		att = &design.AttributeDefinition{
+			Type:     design.String,
+			Metadata: dslengine.MetadataDefinition{"struct:flexible": {"integer", "string"}},
+		}
+	})
+
+	It("names the type after the kinds", func() {
+		Ω(codegen.FlexibleTypeName(att)).Should(Equal("IntegerOrString"))
+		att.Metadata["struct:field:type"] = []string{"IntString"}
+		Ω(codegen.FlexibleTypeName(att)).Should(Equal("IntString"))
+	})
+
+	It("uses the type in struct fields", func() {
+		parent := &design.AttributeDefinition{Type: design.Object{"id": att}}
+		Ω(codegen.GoTypeDef(parent, 0, false, false)).Should(Equal("struct {\n\tID *IntegerOrString\n}"))
+	})
+
+	It("generates the type definition", func() {
+		Ω(codegen.GoFlexibleTypeDef(att)).Should(Equal(flexibleTypeCode))
+	})
+})
+
+const flexibleTypeCode = `// IntegerOrString holds a value that may be decoded from a JSON integer or string.
+type IntegerOrString struct {
+	// Value is the decoded value.
+	Value interface{}
+}
+
+// UnmarshalJSON decodes the data trying each accepted type in order.
+func (f *IntegerOrString) UnmarshalJSON(data []byte) error {
+	{
+		var v int
+		if err := json.Unmarshal(data, &v); err == nil {
+			f.Value = v
+			return nil
+		}
+	}
+	{
+		var v string
+		if err := json.Unmarshal(data, &v); err == nil {
+			f.Value = v
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid IntegerOrString value %s", data)
+}
+
+// MarshalJSON encodes the value.
+func (f IntegerOrString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Value)
+}
+`
@@ -0,0 +1,248 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fromMapT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if fromMapT, err = template.New("fromMap").Parse(fromMapTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoFromMap produces the Go code of the function that builds an instance of the given object user
+// type from a map such as the ones produced when decoding JSON into an interface{}, e.g.
+// "BottleFromMap". The function type-asserts each field, coercing JSON numbers into integers for
+// integer fields, and returns an error if a value does not have the expected type. Fields whose
+// type is an object user type are built by calling the user type own FromMap function.
+// The generated code requires the "fmt" package and depending on the field types the "time",
+// "uuid", "math", "math/big" and "encoding/json" packages.
+func GoFromMap(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: FromMap requires an object user type")
+	}
+	var buf bytes.Buffer
+	writeFromMapFields(&buf, ut.AttributeDefinition, "m", "res", 1)
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": buf.String(),
+	}
+	return RunTemplate(fromMapT, data)
+}
+
+// writeFromMapFields writes the code that initializes the fields of target from the values of the
+// map named src. att must be an object.
+func writeFromMapFields(buf *bytes.Buffer, att *design.AttributeDefinition, src, target string, depth int) {
+	o := att.Type.ToObject()
+	o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		v, val := fmt.Sprintf("v%d", depth), fmt.Sprintf("val%d", depth)
+		writeLine(buf, depth, "if %s, ok := %s[%q]; ok && %s != nil {", v, src, n, v)
+		writeFromMapValue(buf, catt, v, val, n, depth+1)
+		ref := val
+		if !catt.Type.IsObject() && att.IsPrimitivePointer(n) {
+			ref = "&" + val
+		}
+		writeLine(buf, depth+1, "%s.%s = %s", target, goFieldName(n, catt), ref)
+		writeLine(buf, depth, "}")
+		return nil
+	})
+}
+
+// writeFromMapValue writes the code that declares the variable named target and initializes it
+// with the value held by the interface{} named src. context is used to build error messages.
+func writeFromMapValue(buf *bytes.Buffer, att *design.AttributeDefinition, src, target, context string, depth int) {
+	var ut *design.UserTypeDefinition
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		ut = actual
+	case *design.MediaTypeDefinition:
+		ut = actual.UserTypeDefinition
+	}
+	if ut != nil {
+		name := GoTypeName(att.Type, nil, 0, false)
+		if ut.IsObject() {
+			m := fmt.Sprintf("m%d", depth)
+			writeTypeAssertion(buf, src, m, "map[string]interface{}", "object", context, depth)
+			writeLine(buf, depth, "%s, err := %sFromMap(%s)", target, name, m)
+			writeErrorCheck(buf, context, depth)
+			return
+		}
+		raw := fmt.Sprintf("raw%d", depth)
+		writeFromMapValue(buf, ut.AttributeDefinition, src, raw, context, depth)
+		writeLine(buf, depth, "%s := %s(%s)", target, name, raw)
+		return
+	}
+
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		writeTypeAssertion(buf, src, target, "bool", "boolean", context, depth)
+	case design.StringKind:
+		writeTypeAssertion(buf, src, target, "string", "string", context, depth)
+	case design.IntegerKind:
+		n := fmt.Sprintf("n%d", depth)
+		writeLine(buf, depth, "var %s int", target)
+		writeLine(buf, depth, "switch %s := %s.(type) {", n, src)
+		writeLine(buf, depth, "case float64:")
+		writeLine(buf, depth+1, "%s = int(%s)", target, n)
+		writeLine(buf, depth+1, "if float64(%s) != %s {", target, n)
+		writeLine(buf, depth+2, "return nil, fmt.Errorf(\"invalid value for %%q: %%v is not an integer\", %q, %s)", context, n)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth, "case int:")
+		writeLine(buf, depth+1, "%s = %s", target, n)
+		writeLine(buf, depth, "default:")
+		writeLine(buf, depth+1, "return nil, fmt.Errorf(\"invalid type for %%q: expected integer, got %%T\", %q, %s)", context, src)
+		writeLine(buf, depth, "}")
+	case design.NumberKind:
+		n := fmt.Sprintf("n%d", depth)
+		writeLine(buf, depth, "var %s float64", target)
+		writeLine(buf, depth, "switch %s := %s.(type) {", n, src)
+		writeLine(buf, depth, "case float64:")
+		writeLine(buf, depth+1, "%s = %s", target, n)
+		writeLine(buf, depth, "case int:")
+		writeLine(buf, depth+1, "%s = float64(%s)", target, n)
+		writeLine(buf, depth, "default:")
+		writeLine(buf, depth+1, "return nil, fmt.Errorf(\"invalid type for %%q: expected number, got %%T\", %q, %s)", context, src)
+		writeLine(buf, depth, "}")
+	case design.DateTimeKind:
+		s := fmt.Sprintf("s%d", depth)
+		writeTypeAssertion(buf, src, s, "string", "string", context, depth)
+		writeLine(buf, depth, "%s, err := time.Parse(time.RFC3339, %s)", target, s)
+		writeErrorCheck(buf, context, depth)
+	case design.UUIDKind:
+		s := fmt.Sprintf("s%d", depth)
+		writeTypeAssertion(buf, src, s, "string", "string", context, depth)
+		writeLine(buf, depth, "%s, err := uuid.FromString(%s)", target, s)
+		writeErrorCheck(buf, context, depth)
+	case design.DurationKind:
+		s := fmt.Sprintf("s%d", depth)
+		writeTypeAssertion(buf, src, s, "string", "string", context, depth)
+		writeLine(buf, depth, "%s, err := goa.ParseDuration(%s)", target, s)
+		writeErrorCheck(buf, context, depth)
+	case design.RawJSONKind:
+		b := fmt.Sprintf("b%d", depth)
+		writeLine(buf, depth, "%s, err := json.Marshal(%s)", b, src)
+		writeErrorCheck(buf, context, depth)
+		writeLine(buf, depth, "%s := json.RawMessage(%s)", target, b)
+	case design.BigIntKind:
+		n := fmt.Sprintf("n%d", depth)
+		writeLine(buf, depth, "var %s *big.Int", target)
+		writeLine(buf, depth, "switch %s := %s.(type) {", n, src)
+		writeLine(buf, depth, "case string:")
+		writeLine(buf, depth+1, "var ok bool")
+		writeLine(buf, depth+1, "if %s, ok = new(big.Int).SetString(%s, 10); !ok {", target, n)
+		writeLine(buf, depth+2, "return nil, fmt.Errorf(\"invalid value for %%q: %%q is not an integer\", %q, %s)", context, n)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth, "case float64:")
+		writeLine(buf, depth+1, "if %s != math.Trunc(%s) {", n, n)
+		writeLine(buf, depth+2, "return nil, fmt.Errorf(\"invalid value for %%q: %%v is not an integer\", %q, %s)", context, n)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth+1, "%s, _ = new(big.Float).SetFloat64(%s).Int(nil)", target, n)
+		writeLine(buf, depth, "case int:")
+		writeLine(buf, depth+1, "%s = big.NewInt(int64(%s))", target, n)
+		writeLine(buf, depth, "default:")
+		writeLine(buf, depth+1, "return nil, fmt.Errorf(\"invalid type for %%q: expected integer, got %%T\", %q, %s)", context, src)
+		writeLine(buf, depth, "}")
+	case design.AnyKind:
+		writeLine(buf, depth, "%s := %s", target, src)
+	case design.ArrayKind:
+		a, e, elem := fmt.Sprintf("a%d", depth), fmt.Sprintf("e%d", depth), fmt.Sprintf("elem%d", depth)
+		writeTypeAssertion(buf, src, a, "[]interface{}", "array", context, depth)
+		writeLine(buf, depth, "%s := make(%s, len(%s))", target, goValueTypeRef(att, depth), a)
+		writeLine(buf, depth, "for i, %s := range %s {", e, a)
+		writeFromMapValue(buf, att.Type.ToArray().ElemType, e, elem, context, depth+1)
+		writeLine(buf, depth+1, "%s[i] = %s", target, elem)
+		writeLine(buf, depth, "}")
+	case design.HashKind:
+		h := att.Type.ToHash()
+		if h.KeyType.Type.Kind() != design.StringKind {
+			panic("invalid hash key type: FromMap requires string hash keys")
+		}
+		m, e, elem := fmt.Sprintf("m%d", depth), fmt.Sprintf("e%d", depth), fmt.Sprintf("elem%d", depth)
+		writeTypeAssertion(buf, src, m, "map[string]interface{}", "object", context, depth)
+		writeLine(buf, depth, "%s := make(%s, len(%s))", target, goValueTypeRef(att, depth), m)
+		writeLine(buf, depth, "for k, %s := range %s {", e, m)
+		writeFromMapValue(buf, h.ElemType, e, elem, context, depth+1)
+		writeLine(buf, depth+1, "%s[k] = %s", target, elem)
+		writeLine(buf, depth, "}")
+	case design.ObjectKind:
+		m := fmt.Sprintf("m%d", depth)
+		writeTypeAssertion(buf, src, m, "map[string]interface{}", "object", context, depth)
+		writeLine(buf, depth, "%s := new(%s)", target, GoTypeDef(att, depth, true, false))
+		writeFromMapFields(buf, att, m, target, depth)
+	default:
+		panic("goa bug: unknown data structure type")
+	}
+}
+
+// writeTypeAssertion writes the code that asserts that the interface{} named src holds a value of
+// the given Go type and stores it in the variable named target.
+func writeTypeAssertion(buf *bytes.Buffer, src, target, typ, desc, context string, depth int) {
+	writeLine(buf, depth, "%s, ok := %s.(%s)", target, src, typ)
+	writeLine(buf, depth, "if !ok {")
+	writeLine(buf, depth+1, "return nil, fmt.Errorf(\"invalid type for %%q: expected %s, got %%T\", %q, %s)", desc, context, src)
+	writeLine(buf, depth, "}")
+}
+
+// GoTypeAssert produces the Go code that declares the variable named dstVar holding the value of
+// the given Go type stored in the interface{} named srcVar. The code returns an error that
+// includes fieldPath and the expected type from the enclosing function if srcVar holds a value
+// of another type, the enclosing function must thus only return an error. The pointer and value
+// forms of the type are both accepted: if goType is a pointer type the address of a value of the
+// element type is used and dereferenced pointers are used for other types which then must not be
+// nil. The lines are not indented, see Indent.
+func GoTypeAssert(goType, srcVar, dstVar, fieldPath string) string {
+	v := uniqueName("v", map[string]bool{srcVar: true, dstVar: true})
+	elem, isPtr := strings.TrimPrefix(goType, "*"), strings.HasPrefix(goType, "*")
+	var buf bytes.Buffer
+	writeLine(&buf, 0, "var %s %s", dstVar, goType)
+	writeLine(&buf, 0, "switch %s := %s.(type) {", v, srcVar)
+	if isPtr {
+		writeLine(&buf, 0, "case %s:", goType)
+		writeLine(&buf, 1, "%s = %s", dstVar, v)
+		writeLine(&buf, 0, "case %s:", elem)
+		writeLine(&buf, 1, "%s = &%s", dstVar, v)
+	} else {
+		writeLine(&buf, 0, "case %s:", goType)
+		writeLine(&buf, 1, "%s = %s", dstVar, v)
+		writeLine(&buf, 0, "case *%s:", goType)
+		writeLine(&buf, 1, "if %s == nil {", v)
+		writeLine(&buf, 2, "return fmt.Errorf(\"invalid value for %%q: expected %s, got nil\", %q)", goType, fieldPath)
+		writeLine(&buf, 1, "}")
+		writeLine(&buf, 1, "%s = *%s", dstVar, v)
+	}
+	writeLine(&buf, 0, "default:")
+	writeLine(&buf, 1, "return fmt.Errorf(\"invalid type for %%q: expected %s, got %%T\", %q, %s)", goType, fieldPath, srcVar)
+	writeLine(&buf, 0, "}")
+	return buf.String()
+}
+
+// writeErrorCheck writes the code that returns the error named err if it is not nil.
+func writeErrorCheck(buf *bytes.Buffer, context string, depth int) {
+	writeLine(buf, depth, "if err != nil {")
+	writeLine(buf, depth+1, "return nil, fmt.Errorf(\"invalid value for %%q: %%s\", %q, err)", context)
+	writeLine(buf, depth, "}")
+}
+
+// writeLine writes a line of code indented with depth tabs.
+func writeLine(buf *bytes.Buffer, depth int, format string, a ...interface{}) {
+	WriteTabs(buf, depth)
+	buf.WriteString(fmt.Sprintf(format, a...))
+	buf.WriteByte('\n')
+}
+
+const fromMapTmpl = `// {{ .Name }}FromMap builds a {{ .Name }} from the given map, typically the result of decoding
+// JSON into an interface{}.
+func {{ .Name }}FromMap(m map[string]interface{}) (*{{ .Name }}, error) {
+	res := new({{ .Name }})
+{{ .Fields }}	return res, nil
+}
+`
@@ -0,0 +1,158 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFromMap", func() {
+	var ut *design.UserTypeDefinition
+
+	Context("given an object user type", func() {
+		BeforeEach(func() {
+			child := &design.UserTypeDefinition{
+				TypeName:            "child",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+			}
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"count": &design.AttributeDefinition{Type: design.Integer},
+						"child": &design.AttributeDefinition{Type: child},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+			}
+		})
+
+		It("produces the FromMap function", func() {
+			Ω(codegen.GoFromMap(ut)).Should(Equal(fromMapCode))
+		})
+	})
+
+	Context("given collections of inline objects", func() {
+		BeforeEach(func() {
+			inline := &design.AttributeDefinition{
+				Type: design.Object{"x": &design.AttributeDefinition{Type: design.Integer}},
+			}
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"items": &design.AttributeDefinition{Type: &design.Array{ElemType: inline}},
+						"index": &design.AttributeDefinition{Type: &design.Hash{
+							KeyType:  &design.AttributeDefinition{Type: design.String},
+							ElemType: inline,
+						}},
+					},
+				},
+			}
+		})
+
+		It("generates code that compiles", func() {
+			code := "type Bottle " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + codegen.GoFromMap(ut)
+			Ω(typeCheck(code, "fmt")).Should(Succeed())
+		})
+	})
+
+	Context("given a hash with non string keys", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"ids": &design.AttributeDefinition{Type: &design.Hash{
+							KeyType:  &design.AttributeDefinition{Type: design.Integer},
+							ElemType: &design.AttributeDefinition{Type: design.String},
+						}},
+					},
+				},
+			}
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoFromMap(ut) }).Should(Panic())
+		})
+	})
+})
+
+var _ = Describe("GoTypeAssert", func() {
+	It("accepts values and non nil pointers for value types", func() {
+		Ω(codegen.GoTypeAssert("string", "raw", "name", "bottle.name")).Should(Equal(`var name string
+switch v := raw.(type) {
+case string:
+	name = v
+case *string:
+	if v == nil {
+		return fmt.Errorf("invalid value for %q: expected string, got nil", "bottle.name")
+	}
+	name = *v
+default:
+	return fmt.Errorf("invalid type for %q: expected string, got %T", "bottle.name", raw)
+}
+`))
+	})
+
+	It("accepts pointers and values for pointer types", func() {
+		Ω(codegen.GoTypeAssert("*Bottle", "raw", "b", "payload.bottle")).Should(Equal(`var b *Bottle
+switch v := raw.(type) {
+case *Bottle:
+	b = v
+case Bottle:
+	b = &v
+default:
+	return fmt.Errorf("invalid type for %q: expected *Bottle, got %T", "payload.bottle", raw)
+}
+`))
+	})
+
+	It("does not shadow the variables", func() {
+		Ω(codegen.GoTypeAssert("int", "v", "n", "count")).Should(HavePrefix("var n int\nswitch v2 := v.(type) {\n"))
+	})
+})
+
+const fromMapCode = `// BottleFromMap builds a Bottle from the given map, typically the result of decoding
+// JSON into an interface{}.
+func BottleFromMap(m map[string]interface{}) (*Bottle, error) {
+	res := new(Bottle)
+	if v1, ok := m["child"]; ok && v1 != nil {
+		m2, ok := v1.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid type for %q: expected object, got %T", "child", v1)
+		}
+		val1, err := ChildFromMap(m2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %s", "child", err)
+		}
+		res.Child = val1
+	}
+	if v1, ok := m["count"]; ok && v1 != nil {
+		var val1 int
+		switch n2 := v1.(type) {
+		case float64:
+			val1 = int(n2)
+			if float64(val1) != n2 {
+				return nil, fmt.Errorf("invalid value for %q: %v is not an integer", "count", n2)
+			}
+		case int:
+			val1 = n2
+		default:
+			return nil, fmt.Errorf("invalid type for %q: expected integer, got %T", "count", v1)
+		}
+		res.Count = &val1
+	}
+	if v1, ok := m["name"]; ok && v1 != nil {
+		val1, ok := v1.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type for %q: expected string, got %T", "name", v1)
+		}
+		res.Name = val1
+	}
+	return res, nil
+}
+`
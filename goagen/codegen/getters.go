@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// GetterMode selects the getters produced by GoGetters.
+type GetterMode int
+
+const (
+	// ZeroValueGetters produces getters that return the value of the field or the zero value
+	// if the field is not set, e.g. "GetRating() int".
+	ZeroValueGetters GetterMode = 1 << iota
+	// OKGetters produces getters that return the value of the field and whether it is set like
+	// map accesses do, e.g. "RatingOK() (int, bool)".
+	OKGetters
+	// AllGetters produces both the zero value and the (value, ok) getters.
+	AllGetters = ZeroValueGetters | OKGetters
+)
+
+var gettersT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if gettersT, err = template.New("getters").Parse(gettersTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// getterField describes an optional field and the names of its getters.
+type getterField struct {
+	// Name is the name of the struct field.
+	Name string
+	// Type is the Go type of the field value.
+	Type string
+	// Get is the name of the zero value getter if any.
+	Get string
+	// OK is the name of the (value, ok) getter if any.
+	OK string
+}
+
+// GoGetters produces the Go code of the getters of the optional primitive fields of the struct
+// generated for the given object user type, mode selects the getters that are produced. The
+// getters dereference the field pointers and may be called on nil instances. A getter whose name
+// is already taken by a field or another getter gets a numeric suffix, e.g. "RatingOK2" if the
+// type also has a "rating_ok" attribute. The function returns the empty string if the type has
+// no optional primitive field.
+func GoGetters(ut *design.UserTypeDefinition, mode GetterMode) string {
+	if !ut.IsObject() {
+		panic("goa bug: getters require an object user type")
+	}
+	att := ut.AttributeDefinition
+	taken := make(map[string]bool)
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		taken[goFieldName(n, catt)] = true
+		return nil
+	})
+	var fields []*getterField
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if catt.Type.IsObject() || !att.IsPrimitivePointer(n) {
+			return nil
+		}
+		f := &getterField{
+			Name: goFieldName(n, catt),
+			Type: GoTypeName(catt.Type, nil, 0, false),
+		}
+		if mode&ZeroValueGetters != 0 {
+			f.Get = uniqueName("Get"+f.Name, taken)
+		}
+		if mode&OKGetters != 0 {
+			f.OK = uniqueName(f.Name+"OK", taken)
+		}
+		fields = append(fields, f)
+		return nil
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": fields,
+	}
+	return RunTemplate(gettersT, data)
+}
+
+// uniqueName returns name if it is not in taken, name followed by the smallest integer greater
+// than 1 that makes it unique otherwise. The returned name is added to taken.
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+const gettersTmpl = `{{ $name := .Name }}{{ range $i, $f := .Fields }}{{ if $i }}
+{{ end }}{{ if $f.Get }}// {{ $f.Get }} returns the value of the {{ $f.Name }} field, the zero value if it is not set.
+func (ut *{{ $name }}) {{ $f.Get }}() {{ $f.Type }} {
+	if ut == nil || ut.{{ $f.Name }} == nil {
+		var zero {{ $f.Type }}
+		return zero
+	}
+	return *ut.{{ $f.Name }}
+}
+{{ end }}{{ if and $f.Get $f.OK }}
+{{ end }}{{ if $f.OK }}// {{ $f.OK }} returns the value of the {{ $f.Name }} field and true if it is set, the zero value
+// and false otherwise.
+func (ut *{{ $name }}) {{ $f.OK }}() ({{ $f.Type }}, bool) {
+	if ut == nil || ut.{{ $f.Name }} == nil {
+		var zero {{ $f.Type }}
+		return zero, false
+	}
+	return *ut.{{ $f.Name }}, true
+}
+{{ end }}{{ end }}`
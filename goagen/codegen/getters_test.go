@@ -0,0 +1,74 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoGetters", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"origin": &design.AttributeDefinition{Type: design.Object{}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	It("produces zero value getters", func() {
+		Ω(codegen.GoGetters(ut, codegen.ZeroValueGetters)).Should(Equal(zeroValueGettersCode))
+	})
+
+	It("produces (value, ok) getters", func() {
+		Ω(codegen.GoGetters(ut, codegen.OKGetters)).Should(Equal(okGettersCode))
+	})
+
+	It("produces both getters", func() {
+		Ω(codegen.GoGetters(ut, codegen.AllGetters)).Should(Equal(zeroValueGettersCode + "\n" + okGettersCode))
+	})
+
+	It("avoids names taken by fields", func() {
+		ut.Type.ToObject()["rating_ok"] = &design.AttributeDefinition{Type: design.Boolean}
+		code := codegen.GoGetters(ut, codegen.OKGetters)
+		Ω(code).Should(ContainSubstring("func (ut *Bottle) RatingOK2() (int, bool) {"))
+		Ω(code).Should(ContainSubstring("func (ut *Bottle) RatingOKOK() (bool, bool) {"))
+	})
+
+	It("returns the empty string if there is no optional primitive field", func() {
+		delete(ut.Type.ToObject(), "rating")
+		Ω(codegen.GoGetters(ut, codegen.AllGetters)).Should(BeEmpty())
+	})
+})
+
+const (
+	zeroValueGettersCode = `// GetRating returns the value of the Rating field, the zero value if it is not set.
+func (ut *Bottle) GetRating() int {
+	if ut == nil || ut.Rating == nil {
+		var zero int
+		return zero
+	}
+	return *ut.Rating
+}
+`
+
+	okGettersCode = `// RatingOK returns the value of the Rating field and true if it is set, the zero value
+// and false otherwise.
+func (ut *Bottle) RatingOK() (int, bool) {
+	if ut == nil || ut.Rating == nil {
+		var zero int
+		return zero, false
+	}
+	return *ut.Rating, true
+}
+`
+)
@@ -0,0 +1,37 @@
+package codegen_test
+
+import (
+	"testing"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// benchmarkNames lists typical attribute names, they are all ASCII.
+var benchmarkNames = []string{
+	"id", "name", "created_at", "updatedAt", "user_id", "APIKey", "X-Request-ID",
+	"http_server_url", "v1_api_2", "accountBalance", "is_active", "ipv6_addr",
+}
+
+// BenchmarkGoifyASCII measures Goify on ASCII names which take the byte based fast path.
+func BenchmarkGoifyASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, n := range benchmarkNames {
+			codegen.Goify(n, true)
+		}
+	}
+}
+
+// BenchmarkGoifyUnicode measures Goify on the same names suffixed with a non-ASCII character
+// that is dropped so that they produce the same identifiers through the rune based path.
+func BenchmarkGoifyUnicode(b *testing.B) {
+	names := make([]string, len(benchmarkNames))
+	for i, n := range benchmarkNames {
+		names[i] = n + "§"
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, n := range names {
+			codegen.Goify(n, true)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package codegen_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// updateGolden causes the golden files to be rewritten with the current output, run
+// "go test -update" after a deliberate change and review the diff.
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// goifyCorpus lists the names run through Goify by the golden test. The names are grouped by the
+// feature they exercise, add new names at the end of the relevant group.
+var goifyCorpus = []string{
+	// Empty and degenerate names
+	"", "_", "__", "-", " ", "%", "a", "A", "1", "_1",
+
+	// Single words
+	"foo", "Foo", "FOO", "fOO", "bottle", "type", "func", "string", "int", "error",
+
+	// Separators
+	"foo_bar", "foo-bar", "foo bar", "foo.bar", "foo/bar", "foo__bar", "foo--bar",
+	"_foo", "foo_", "-foo-", "foo_bar_baz", "foo - bar", "foo:bar", "foo+bar",
+
+	// Camel case
+	"fooBar", "FooBar", "fooBAR", "FOOBar", "fooBarBaz", "userID", "UserId", "userId",
+	"XMLHttpRequest", "getHTTPResponseCode",
+
+	// Initialisms
+	"id", "ID", "api_key", "apikey", "http_server", "httpserver", "url", "uri_path",
+	"json_api", "ssh_key", "ip_address", "uuid", "ui", "tls_config", "utf8", "xsrf_token",
+	"html", "cpu", "ttl", "acl_rules",
+
+	// Digits
+	"1foo", "123", "foo1", "foo_1", "foo1bar", "http2server", "v1", "v1_api", "v1_api_2", "oauth2_token",
+	"ipv4", "ipv6_addr", "md5sum", "sha256_hash", "x509cert", "f00_b4r",
+
+	// Headers and other HTTP names
+	"Accept-Encoding", "Accept--Encoding", "X-Request-ID", "x-api-key", "Content-MD5",
+	"WWW-Authenticate", "If-None-Match",
+
+	// Invalid characters
+	"foo%", "%foo", "foo%bar", "foo$bar", "a&b", "foo!!bar", "(foo)", "[bar]", "foo@example.com",
+
+	// Unicode
+	"café", "cafe\u0301", "über_cool", "naïve", "日本", "日本_語", "ñandú", "Ωmega",
+
+	// Names whose words change once Goified
+	"uRi", "HId", "Urlr", "dv-uRi", "xSs%IdUrlr", "hT Tl2a",
+
+	// Single letter first words
+	"aUser", "xRay", "eCommerce", "XUser", "a_user", "iPhone", "iD",
+
+	// Apostrophes
+	"user's_profile", "O'Brien", "it\u2019s", `say"hello"`,
+}
+
+// goifyCombinations returns all the combinations of GoifyOptions in a fixed order.
+func goifyCombinations() []codegen.GoifyOptions {
+	var opts []codegen.GoifyOptions
+	for i := 0; i < 16; i++ {
+		opts = append(opts, codegen.GoifyOptions{
+			FirstUpper:       i&1 != 0,
+			SplitInitialisms: i&2 != 0,
+			SplitDigits:      i&4 != 0,
+			ExactInitialisms: i&8 != 0,
+		})
+	}
+	return opts
+}
+
+// goifyTable runs the corpus through all the combinations of options and produces one line per
+// name listing the name, quoted with non ASCII characters escaped so that different encodings of
+// the same text can be told apart, followed by the output of each combination.
+func goifyTable() []byte {
+	var buf bytes.Buffer
+	opts := goifyCombinations()
+	buf.WriteString("# name")
+	for _, o := range opts {
+		fmt.Fprintf(&buf, "\t%+v", o)
+	}
+	buf.WriteByte('\n')
+	for _, name := range goifyCorpus {
+		fmt.Fprintf(&buf, "%+q", name)
+		for _, o := range opts {
+			fmt.Fprintf(&buf, "\t%q", codegen.GoifyWith(name, o))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+var _ = Describe("Goify golden", func() {
+	golden := filepath.Join("testdata", "goify.golden")
+
+	It("is deterministic", func() {
+		Ω(goifyTable()).Should(Equal(goifyTable()))
+	})
+
+	It("produces the recorded identifiers", func() {
+		actual := goifyTable()
+		if *updateGolden {
+			Ω(ioutil.WriteFile(golden, actual, 0644)).Should(Succeed())
+		}
+		expected, err := ioutil.ReadFile(golden)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(actual)).Should(Equal(string(expected)))
+	})
+})
+
+var _ = Describe("Goify idempotence", func() {
+	It("leaves Goified names unchanged", func() {
+		for _, name := range goifyCorpus {
+			for _, upper := range []bool{true, false} {
+				once := codegen.Goify(name, upper)
+				Ω(codegen.Goify(once, upper)).Should(Equal(once), fmt.Sprintf("Goify(%+q, %v)", name, upper))
+			}
+		}
+	})
+
+	It("leaves names Goified with options unchanged", func() {
+		for _, name := range goifyCorpus {
+			for _, o := range goifyCombinations() {
+				once := codegen.GoifyWith(name, o)
+				Ω(codegen.GoifyWith(once, o)).Should(Equal(once), fmt.Sprintf("GoifyWith(%+q, %+v)", name, o))
+			}
+		}
+	})
+})
+
+var _ = Describe("Goify ASCII fast path", func() {
+	// Names made of ASCII characters only take a byte based path, suffixing them with a non
+	// ASCII character that is dropped forces the rune based path without changing the words.
+	const forceRunes = "§"
+
+	It("splits the same words as the rune based path", func() {
+		for _, name := range goifyCorpus {
+			Ω(codegen.SplitWords(name)).Should(Equal(codegen.SplitWords(name+forceRunes)), fmt.Sprintf("SplitWords(%+q)", name))
+		}
+	})
+
+	It("produces the same identifiers as the rune based path", func() {
+		for _, name := range goifyCorpus {
+			if name == "" {
+				// the suffixed name is not empty and produces the "_" fallback
+				continue
+			}
+			for _, o := range goifyCombinations() {
+				Ω(codegen.GoifyWith(name, o)).Should(Equal(codegen.GoifyWith(name+forceRunes, o)), fmt.Sprintf("GoifyWith(%+q, %+v)", name, o))
+			}
+		}
+	})
+})
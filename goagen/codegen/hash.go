@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var hashMethodT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if hashMethodT, err = template.New("hashMethod").Parse(hashMethodTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoHashMethod produces the Go code of the Hash method of the given object user type. The method
+// computes a FNV-1a hash of the field values written in a deterministic order so that equal values
+// produce equal hashes across runs: fields are written in alphabetical order and the entries of
+// maps are combined independently of their order. Nil pointers are written as a distinct marker.
+// Fields whose type is an object user type are hashed by calling their own Hash method.
+// The generated code requires the "fmt" and "hash/fnv" packages and the "time" package if the
+// type has date time fields.
+func GoHashMethod(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: Hash method requires an object user type")
+	}
+	var buf bytes.Buffer
+	writeHashFields(&buf, ut.AttributeDefinition, "ut", "h", 1)
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": buf.String(),
+	}
+	return RunTemplate(hashMethodT, data)
+}
+
+// writeHashFields writes the code that writes the fields of target into the hasher named h.
+// att must be an object.
+func writeHashFields(buf *bytes.Buffer, att *design.AttributeDefinition, target, h string, depth int) {
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		field := fmt.Sprintf("%s.%s", target, goFieldName(n, catt))
+		if !catt.Type.IsObject() && att.IsPrimitivePointer(n) {
+			writeHashNilCheck(buf, field, h, depth)
+			writeHashValue(buf, catt, "*"+field, h, depth+1)
+			writeLine(buf, depth, "}")
+			return nil
+		}
+		writeHashValue(buf, catt, field, h, depth)
+		return nil
+	})
+}
+
+// writeHashValue writes the code that writes the value of target into the hasher named h.
+func writeHashValue(buf *bytes.Buffer, att *design.AttributeDefinition, target, h string, depth int) {
+	var ut *design.UserTypeDefinition
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		ut = actual
+	case *design.MediaTypeDefinition:
+		ut = actual.UserTypeDefinition
+	}
+	if ut != nil {
+		if ut.IsObject() {
+			writeHashNilCheck(buf, target, h, depth)
+			writeLine(buf, depth+1, "fmt.Fprintf(%s, \"%%d;\", %s.Hash())", h, target)
+			writeLine(buf, depth, "}")
+			return
+		}
+		writeHashValue(buf, ut.AttributeDefinition, target, h, depth)
+		return
+	}
+
+	switch att.Type.Kind() {
+	case design.BooleanKind, design.IntegerKind, design.NumberKind, design.DurationKind:
+		writeLine(buf, depth, "fmt.Fprintf(%s, \"%%v;\", %s)", h, target)
+	case design.StringKind, design.UUIDKind:
+		writeLine(buf, depth, "fmt.Fprintf(%s, \"%%q\", %s)", h, target)
+	case design.DateTimeKind:
+		if strings.HasPrefix(target, "*") {
+			target = "(" + target + ")"
+		}
+		writeLine(buf, depth, "fmt.Fprintf(%s, \"%%q\", %s.UTC().Format(time.RFC3339Nano))", h, target)
+	case design.BigIntKind:
+		writeHashNilCheck(buf, target, h, depth)
+		writeLine(buf, depth+1, "fmt.Fprintf(%s, \"%%s;\", %s)", h, target)
+		writeLine(buf, depth, "}")
+	case design.RawJSONKind:
+		writeHashNilCheck(buf, target, h, depth)
+		writeLine(buf, depth+1, "fmt.Fprintf(%s, \"%%q\", %s)", h, target)
+		writeLine(buf, depth, "}")
+	case design.AnyKind:
+		writeLine(buf, depth, "fmt.Fprintf(%s, \"%%#v;\", %s)", h, target)
+	case design.ArrayKind:
+		e := fmt.Sprintf("e%d", depth)
+		writeLine(buf, depth, "fmt.Fprintf(%s, \"%%d;\", len(%s))", h, target)
+		writeLine(buf, depth, "for _, %s := range %s {", e, target)
+		writeHashValue(buf, att.Type.ToArray().ElemType, e, h, depth+1)
+		writeLine(buf, depth, "}")
+	case design.HashKind:
+		hash := att.Type.ToHash()
+		k, v, sum, eh := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("sum%d", depth), fmt.Sprintf("h%d", depth)
+		// scope the sum in a block so that the hashes of sibling fields do not collide
+		writeLine(buf, depth, "{")
+		writeLine(buf, depth+1, "var %s uint64", sum)
+		writeLine(buf, depth+1, "for %s, %s := range %s {", k, v, target)
+		writeLine(buf, depth+2, "%s := fnv.New64a()", eh)
+		writeHashValue(buf, hash.KeyType, k, eh, depth+2)
+		writeHashValue(buf, hash.ElemType, v, eh, depth+2)
+		writeLine(buf, depth+2, "%s += %s.Sum64()", sum, eh)
+		writeLine(buf, depth+1, "}")
+		writeLine(buf, depth+1, "fmt.Fprintf(%s, \"%%d;\", %s)", h, sum)
+		writeLine(buf, depth, "}")
+	case design.ObjectKind:
+		writeHashNilCheck(buf, target, h, depth)
+		writeHashFields(buf, att, target, h, depth+1)
+		writeLine(buf, depth, "}")
+	default:
+		panic("goa bug: unknown data structure type")
+	}
+}
+
+// writeHashNilCheck writes the code that writes the nil marker into the hasher named h if target
+// is nil and opens the block that writes the value otherwise. The caller closes the block.
+func writeHashNilCheck(buf *bytes.Buffer, target, h string, depth int) {
+	writeLine(buf, depth, "if %s == nil {", target)
+	writeLine(buf, depth+1, "%s.Write([]byte{0})", h)
+	writeLine(buf, depth, "} else {")
+	writeLine(buf, depth+1, "%s.Write([]byte{1})", h)
+}
+
+const hashMethodTmpl = `// Hash returns a hash of the {{ .Name }} field values, equal values produce equal hashes.
+func (ut *{{ .Name }}) Hash() uint64 {
+	if ut == nil {
+		return 0
+	}
+	h := fnv.New64a()
+{{ .Fields }}	return h.Sum64()
+}
+`
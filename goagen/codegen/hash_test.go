@@ -0,0 +1,76 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoHashMethod", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"tags": &design.AttributeDefinition{Type: &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: design.Number},
+					}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	It("writes the fields in a deterministic order", func() {
+		Ω(codegen.GoHashMethod(ut)).Should(Equal(hashMethodCode))
+	})
+
+	Context("with several hash fields", func() {
+		BeforeEach(func() {
+			o := ut.Type.ToObject()
+			o["meta"] = &design.AttributeDefinition{Type: &design.Hash{
+				KeyType:  &design.AttributeDefinition{Type: design.String},
+				ElemType: &design.AttributeDefinition{Type: design.String},
+			}}
+		})
+
+		It("generates code that compiles", func() {
+			code := "type Bottle " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + codegen.GoHashMethod(ut)
+			Ω(typeCheck(code, "fmt", "hash/fnv")).Should(Succeed())
+		})
+	})
+})
+
+const hashMethodCode = `// Hash returns a hash of the Bottle field values, equal values produce equal hashes.
+func (ut *Bottle) Hash() uint64 {
+	if ut == nil {
+		return 0
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%q", ut.Name)
+	if ut.Rating == nil {
+		h.Write([]byte{0})
+	} else {
+		h.Write([]byte{1})
+		fmt.Fprintf(h, "%v;", *ut.Rating)
+	}
+	{
+		var sum1 uint64
+		for k1, v1 := range ut.Tags {
+			h1 := fnv.New64a()
+			fmt.Fprintf(h1, "%q", k1)
+			fmt.Fprintf(h1, "%v;", v1)
+			sum1 += h1.Sum64()
+		}
+		fmt.Fprintf(h, "%d;", sum1)
+	}
+	return h.Sum64()
+}
+`
@@ -0,0 +1,34 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// buildTagRegex matches the build tags accepted by FileHeader, optionally negated.
+var buildTagRegex = regexp.MustCompile(`^!?[A-Za-z0-9_.]+$`)
+
+// FileHeader returns the header of a generated Go file: the "// Code generated by goagen vX,
+// DO NOT EDIT." comment, the build constraint lines if tags is not empty and the package clause.
+// The comment matches the `^// Code generated .* DO NOT EDIT\.$` regular expression that Go tools
+// such as linters use to recognize and skip generated files. The file is only built if all the
+// tags are satisfied, a tag may be negated with a "!" prefix, e.g. []string{"linux", "!appengine"}.
+// The constraint is written both with the "//go:build" syntax and the legacy "// +build" syntax
+// so that it is honored by all Go versions. FileHeader panics if a tag is not a valid build tag.
+func FileHeader(pkg string, tags []string) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by goagen v%s, DO NOT EDIT.\n\n", Version)
+	if len(tags) > 0 {
+		for _, t := range tags {
+			if !buildTagRegex.MatchString(t) {
+				panic(fmt.Sprintf("invalid build tag %#v", t))
+			}
+		}
+		fmt.Fprintf(&buf, "//go:build %s\n", strings.Join(tags, " && "))
+		fmt.Fprintf(&buf, "// +build %s\n\n", strings.Join(tags, ","))
+	}
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+	return buf.String()
+}
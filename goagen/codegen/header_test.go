@@ -0,0 +1,50 @@
+package codegen_test
+
+import (
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileHeader", func() {
+	// generatedRegex is the regular expression Go tools use to detect generated files.
+	generatedRegex := regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+	It("produces a header recognized as generated code", func() {
+		header := codegen.FileHeader("app", nil)
+		Ω(header).Should(Equal("// Code generated by goagen v" + codegen.Version + ", DO NOT EDIT.\n\npackage app\n"))
+		Ω(generatedRegex.MatchString(strings.SplitN(header, "\n", 2)[0])).Should(BeTrue())
+	})
+
+	It("produces the build constraints", func() {
+		header := codegen.FileHeader("app", []string{"linux", "!appengine"})
+		f, err := parser.ParseFile(token.NewFileSet(), "app.go", header, parser.ParseComments)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(f.Name.Name).Should(Equal("app"))
+
+		Ω(header).Should(ContainSubstring("\n//go:build linux && !appengine\n// +build linux,!appengine\n\npackage app\n"))
+		var lines []string
+		for _, l := range strings.Split(header, "\n") {
+			if constraint.IsGoBuild(l) || constraint.IsPlusBuild(l) {
+				lines = append(lines, l)
+			}
+		}
+		Ω(lines).Should(HaveLen(2))
+		for _, l := range lines {
+			expr, err := constraint.Parse(l)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(expr.Eval(func(tag string) bool { return tag == "linux" })).Should(BeTrue())
+			Ω(expr.Eval(func(tag string) bool { return tag == "linux" || tag == "appengine" })).Should(BeFalse())
+		}
+	})
+
+	It("rejects invalid build tags", func() {
+		Ω(func() { codegen.FileHeader("app", []string{"linux darwin"}) }).Should(Panic())
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -87,6 +88,27 @@ func Tabs(depth int) string {
 // Add adds two integers and returns the sum of the two.
 func Add(a, b int) int { return a + b }
 
+// GoMapLiteral produces the Go literal of a map[string]string holding the given pairs with one
+// pair per line sorted by key so that the generated code is deterministic. The keys and values
+// are quoted and escaped as Go strings.
+func GoMapLiteral(pairs map[string]string) string {
+	if len(pairs) == 0 {
+		return "map[string]string{}"
+	}
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteString("map[string]string{\n")
+	for _, k := range keys {
+		writeLine(&buf, 1, "%q: %q,", k, pairs[k])
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
 // CanonicalTemplate returns the resource URI template as a format string suitable for use in the
 // fmt.Printf function family.
 func CanonicalTemplate(r *design.ResourceDefinition) string {
@@ -143,3 +165,51 @@ func SnakeCase(name string) string {
 	}
 	return b.String()
 }
+
+// KebabCase produces the kebab-case version of the given string, e.g. "httpServer" produces
+// "http-server". The words are detected with SplitWords, words that start with an acronym such as
+// "HTTPServer" are split after the acronym and digits stay with the preceding letters, e.g.
+// "utf8Decoder" produces "utf8-decoder". KebabCase is suitable for URL segments and command line
+// flag names.
+func KebabCase(str string) string {
+	return strings.ToLower(strings.Join(caseWords(str), "-"))
+}
+
+// ConstCase produces the CONST_CASE version of the given string, e.g. "httpServer" produces
+// "HTTP_SERVER". The words are detected like with KebabCase. ConstCase is suitable for
+// environment variable names.
+func ConstCase(str string) string {
+	return strings.ToUpper(strings.Join(caseWords(str), "_"))
+}
+
+// caseWords returns the words of str used by KebabCase and ConstCase.
+func caseWords(str string) []string {
+	for u, l := range toLower {
+		str = strings.Replace(str, u, l, -1)
+	}
+	var words []string
+	for _, word := range SplitWords(str) {
+		runes := []rune(word)
+		if len(words) > 0 {
+			// SplitWords ends words before digits, keep them with the preceding letters
+			n := 0
+			for n < len(runes) && unicode.IsDigit(runes[n]) {
+				n++
+			}
+			if n > 0 && n < len(runes) {
+				words[len(words)-1] += string(runes[:n])
+				runes = runes[n:]
+			}
+		}
+		start := 0
+		for i := 1; i+1 < len(runes); i++ {
+			// uppercase->uppercase followed by lowercase ends an acronym
+			if unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1]) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,59 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("name helpers", func() {
+	cases := []struct {
+		name, kebab, constant string
+	}{
+		{"", "", ""},
+		{"foo", "foo", "FOO"},
+		{"httpServer", "http-server", "HTTP_SERVER"},
+		{"HTTPServer", "http-server", "HTTP_SERVER"},
+		{"user_id", "user-id", "USER_ID"},
+		{"createdAt", "created-at", "CREATED_AT"},
+		{"max--Retries 2", "max-retries-2", "MAX_RETRIES_2"},
+		{"utf8Decoder", "utf8-decoder", "UTF8_DECODER"},
+		{"OAuth", "oauth", "OAUTH"},
+	}
+
+	Describe("KebabCase", func() {
+		It("joins lowercase words with dashes", func() {
+			for _, c := range cases {
+				Ω(codegen.KebabCase(c.name)).Should(Equal(c.kebab), c.name)
+			}
+		})
+	})
+
+	Describe("ConstCase", func() {
+		It("joins uppercase words with underscores", func() {
+			for _, c := range cases {
+				Ω(codegen.ConstCase(c.name)).Should(Equal(c.constant), c.name)
+			}
+		})
+	})
+})
+
+var _ = Describe("GoMapLiteral", func() {
+	It("sorts the keys", func() {
+		Ω(codegen.GoMapLiteral(map[string]string{"b": "2", "a": "1", "c": "3"})).Should(Equal("map[string]string{\n" +
+			"\t\"a\": \"1\",\n" +
+			"\t\"b\": \"2\",\n" +
+			"\t\"c\": \"3\",\n" +
+			"}"))
+	})
+
+	It("escapes the keys and values", func() {
+		Ω(codegen.GoMapLiteral(map[string]string{"say \"hi\"": "line\nbreak"})).Should(Equal("map[string]string{\n" +
+			"\t\"say \\\"hi\\\"\": \"line\\nbreak\",\n" +
+			"}"))
+	})
+
+	It("produces an empty literal for empty maps", func() {
+		Ω(codegen.GoMapLiteral(nil)).Should(Equal("map[string]string{}"))
+	})
+})
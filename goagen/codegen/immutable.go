@@ -0,0 +1,85 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var immutableT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if immutableT, err = template.New("immutable").Parse(immutableTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// immutableField describes an unexported field of an immutable struct.
+type immutableField struct {
+	// Field is the name of the unexported struct field.
+	Field string
+	// Getter is the name of the getter, that is the name the field would have in the exported
+	// struct.
+	Getter string
+	// Param is the name of the constructor parameter.
+	Param string
+	// Type is the Go type of the field.
+	Type string
+}
+
+// GoImmutableType produces the Go code that declares the immutable struct of the given object
+// user type: the fields are unexported and hold values as in ValueFields mode, the constructor
+// takes the values of all the fields, e.g. `NewBottle(name string, rating int) *Bottle`, and one
+// getter per field named after the exported field returns its value, e.g. "Rating() int". No
+// other method that modifies the fields is generated. Fields whose types are arrays, hashes or
+// recursive types still share their content with the values given to the constructor. The
+// getters may be called on nil instances and return the zero value then.
+func GoImmutableType(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: immutable structs require an object user type")
+	}
+	att := ut.AttributeDefinition
+	var fields []*immutableField
+	taken := map[string]bool{"ut": true}
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		getter := goFieldName(n, catt)
+		private := Goify(getter, false)
+		typedef := goTypeName(catt.Type, catt.AllRequired(), 1, false, ValueFields, nil)
+		fields = append(fields, &immutableField{
+			Field:  private,
+			Getter: getter,
+			Param:  uniqueName(private, taken),
+			Type:   fieldTypeRef(att, n, typedef, false, ValueFields),
+		})
+		return nil
+	})
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": fields,
+	}
+	return RunTemplate(immutableT, data)
+}
+
+const immutableTmpl = `{{ $name := .Name }}// {{ $name }} is immutable, its fields are set by New{{ $name }} and read with its getters.
+type {{ $name }} struct {
+{{ range .Fields }}	{{ .Field }} {{ .Type }}
+{{ end }}}
+
+// New{{ $name }} returns a {{ $name }} initialized with the given values.
+func New{{ $name }}({{ range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.Param }} {{ $f.Type }}{{ end }}) *{{ $name }} {
+	return &{{ $name }}{
+{{ range .Fields }}		{{ .Field }}: {{ .Param }},
+{{ end }}	}
+}
+{{ range .Fields }}
+// {{ .Getter }} returns the value of the {{ .Field }} field, the zero value if ut is nil.
+func (ut *{{ $name }}) {{ .Getter }}() {{ .Type }} {
+	if ut == nil {
+		var zero {{ .Type }}
+		return zero
+	}
+	return ut.{{ .Field }}
+}
+{{ end }}`
@@ -0,0 +1,80 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoImmutableType", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":     &design.AttributeDefinition{Type: design.Integer},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+			},
+		}
+	})
+
+	It("produces the struct, its constructor and its getters", func() {
+		Ω(codegen.GoImmutableType(ut)).Should(Equal(immutableBottleCode))
+	})
+
+	It("uses value fields for optional attributes and objects", func() {
+		ut.Type.ToObject()["label"] = &design.AttributeDefinition{Type: &design.UserTypeDefinition{
+			TypeName:            "Label",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}}
+		code := codegen.GoImmutableType(ut)
+		Ω(code).Should(ContainSubstring("\tlabel Label\n"))
+		Ω(code).Should(ContainSubstring("func NewBottle(id int, label Label, rating int) *Bottle {"))
+	})
+
+	It("suffixes reserved words", func() {
+		ut.Type.ToObject()["type"] = &design.AttributeDefinition{Type: design.String}
+		code := codegen.GoImmutableType(ut)
+		Ω(code).Should(ContainSubstring("\t\ttype_: type_,\n"))
+		Ω(code).Should(ContainSubstring("func (ut *Bottle) Type() string {"))
+	})
+})
+
+const immutableBottleCode = `// Bottle is immutable, its fields are set by NewBottle and read with its getters.
+type Bottle struct {
+	id int
+	rating int
+}
+
+// NewBottle returns a Bottle initialized with the given values.
+func NewBottle(id int, rating int) *Bottle {
+	return &Bottle{
+		id: id,
+		rating: rating,
+	}
+}
+
+// ID returns the value of the id field, the zero value if ut is nil.
+func (ut *Bottle) ID() int {
+	if ut == nil {
+		var zero int
+		return zero
+	}
+	return ut.id
+}
+
+// Rating returns the value of the rating field, the zero value if ut is nil.
+func (ut *Bottle) Rating() int {
+	if ut == nil {
+		var zero int
+		return zero
+	}
+	return ut.rating
+}
+`
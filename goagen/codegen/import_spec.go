@@ -1,6 +1,14 @@
 package codegen
 
-import "fmt"
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
 
 // ImportSpec defines a generated import statement.
 type ImportSpec struct {
@@ -8,6 +16,17 @@ type ImportSpec struct {
 	Path string
 }
 
+// ImportSet collects the imports of a generated file. Imports of distinct paths that share the
+// same package name are aliased deterministically in the order they are added: the first import
+// keeps the package name and the following ones get the name suffixed with a counter (e.g.
+// "types", "types2", "types3").
+type ImportSet struct {
+	specs []*ImportSpec
+	names map[string]string // package names indexed by import path
+	taken map[string]bool
+	from  string // import path of the package whose types are not qualified
+}
+
 // NewImport creates an import spec.
 func NewImport(name, path string) *ImportSpec {
 	return &ImportSpec{Name: name, Path: path}
@@ -18,6 +37,15 @@ func SimpleImport(path string) *ImportSpec {
 	return &ImportSpec{Path: path}
 }
 
+// NewImportSet creates an import set initialized with the given imports.
+func NewImportSet(imports ...*ImportSpec) *ImportSet {
+	s := &ImportSet{names: make(map[string]string), taken: make(map[string]bool)}
+	for _, imp := range imports {
+		s.Add(imp)
+	}
+	return s
+}
+
 // Code returns the Go import statement for the ImportSpec.
 func (s *ImportSpec) Code() string {
 	if len(s.Name) > 0 {
@@ -25,3 +53,103 @@ func (s *ImportSpec) Code() string {
 	}
 	return fmt.Sprintf(`"%s"`, s.Path)
 }
+
+// Add adds the given import to the set if not already present and returns the name used to
+// qualify the identifiers of the imported package. The package name defaults to the name derived
+// from the import path by packageName, the import is aliased explicitly if that name differs from
+// the last element of the path.
+func (s *ImportSet) Add(imp *ImportSpec) string {
+	if name, ok := s.names[imp.Path]; ok {
+		return name
+	}
+	name := imp.Name
+	if name == "" {
+		name = packageName(imp.Path)
+		if name != path.Base(imp.Path) {
+			imp = NewImport(name, imp.Path)
+		}
+	}
+	if name != "_" && name != "." {
+		alias, i := name, 2
+		for s.taken[alias] {
+			alias = name + strconv.Itoa(i)
+			i++
+		}
+		s.taken[alias] = true
+		if alias != name {
+			imp = NewImport(alias, imp.Path)
+		}
+		name = alias
+	}
+	s.names[imp.Path] = name
+	s.specs = append(s.specs, imp)
+	return name
+}
+
+// packageName returns the name of the package with the given import path: the last element of the
+// path (or the previous one if it is a major version such as "v2") stripped of the ".vN" version
+// suffix and of the "go." or "go-" prefix, e.g. "yaml" for "gopkg.in/yaml.v2" and "uuid" for
+// "github.com/satori/go.uuid". The name is made a valid identifier with GoPackageName if needed.
+func packageName(p string) string {
+	name := path.Base(p)
+	if isMajorVersion(name) && path.Dir(p) != "." {
+		name = path.Base(path.Dir(p))
+	}
+	if i := strings.LastIndex(name, "."); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	if trimmed := strings.TrimPrefix(strings.TrimPrefix(name, "go."), "go-"); trimmed != "" {
+		name = trimmed
+	}
+	if !token.IsIdentifier(name) {
+		name = GoPackageName(name)
+	}
+	return name
+}
+
+// isMajorVersion returns true if elem is a major version path element such as "v2".
+func isMajorVersion(elem string) bool {
+	return len(elem) > 1 && elem[0] == 'v' && isDigits(elem[1:])
+}
+
+// Imports returns the imports of the set in the order they were added.
+func (s *ImportSet) Imports() []*ImportSpec {
+	return s.specs
+}
+
+// GoTypeName is GoTypeName where the names of the user types that define the "struct:pkg:path"
+// metadata are qualified with the name of the corresponding package. The package is added to the
+// set if needed.
+func (s *ImportSet) GoTypeName(t design.DataType, required []string, tabs int, private bool) string {
+	return goTypeName(t, required, tabs, private, PointerFields, s)
+}
+
+// GoTypeRef is GoTypeRef where the names of the user types that define the "struct:pkg:path"
+// metadata are qualified with the name of the corresponding package. The package is added to the
+// set if needed.
+func (s *ImportSet) GoTypeRef(t design.DataType, required []string, tabs int, private bool) string {
+	return goTypeRef(t, required, tabs, private, PointerFields, s)
+}
+
+// GoTypeRefQualified is GoTypeRef for code that is part of the package with import path fromPkg:
+// the names of the user types that define the "struct:pkg:path" metadata are qualified with the
+// name of the corresponding package unless it is fromPkg. The package is added to the set if
+// needed.
+func (s *ImportSet) GoTypeRefQualified(dt design.DataType, fromPkg string) string {
+	prev := s.from
+	s.from = fromPkg
+	defer func() { s.from = prev }()
+	return goTypeRef(dt, nil, 0, false, PointerFields, s)
+}
+
+// qualify prefixes name with the name of the package of ut if ut is defined in another package.
+// qualify returns name unchanged if s is nil.
+func (s *ImportSet) qualify(ut *design.UserTypeDefinition, name string) string {
+	if s == nil || ut.Metadata == nil {
+		return name
+	}
+	if p, ok := ut.Metadata["struct:pkg:path"]; ok && len(p) > 0 && p[0] != s.from {
+		return s.Add(SimpleImport(p[0])) + "." + name
+	}
+	return name
+}
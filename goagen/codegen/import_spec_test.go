@@ -0,0 +1,94 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportSet", func() {
+	var imports *codegen.ImportSet
+
+	BeforeEach(func() {
+		imports = codegen.NewImportSet(codegen.SimpleImport("fmt"))
+	})
+
+	Context("given imports whose package names collide", func() {
+		var names []string
+
+		BeforeEach(func() {
+			names = []string{
+				imports.Add(codegen.SimpleImport("example.com/foo/types")),
+				imports.Add(codegen.SimpleImport("example.com/bar/types")),
+				imports.Add(codegen.SimpleImport("example.com/foo/types")),
+			}
+		})
+
+		It("aliases the imports deterministically", func() {
+			Ω(names).Should(Equal([]string{"types", "types2", "types"}))
+			Ω(imports.Imports()).Should(HaveLen(3))
+			Ω(imports.Imports()[1].Code()).Should(Equal(`"example.com/foo/types"`))
+			Ω(imports.Imports()[2].Code()).Should(Equal(`types2 "example.com/bar/types"`))
+		})
+	})
+
+	Context("given import paths whose last element is not a package name", func() {
+		var names []string
+
+		BeforeEach(func() {
+			names = []string{
+				imports.Add(codegen.SimpleImport("gopkg.in/yaml.v2")),
+				imports.Add(codegen.SimpleImport("github.com/satori/go.uuid")),
+				imports.Add(codegen.SimpleImport("github.com/armon/go-metrics")),
+				imports.Add(codegen.SimpleImport("example.com/foo/v2")),
+				imports.Add(codegen.SimpleImport("example.com/bar/v2")),
+			}
+		})
+
+		It("derives valid package names", func() {
+			Ω(names).Should(Equal([]string{"yaml", "uuid", "metrics", "foo", "bar"}))
+			Ω(imports.Imports()[1].Code()).Should(Equal(`yaml "gopkg.in/yaml.v2"`))
+			Ω(imports.Imports()[4].Code()).Should(Equal(`foo "example.com/foo/v2"`))
+		})
+	})
+
+	Context("given user types defined in other packages", func() {
+		var foo, bar *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			foo = &design.UserTypeDefinition{
+				TypeName: "foo",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     design.Object{},
+					Metadata: dslengine.MetadataDefinition{"struct:pkg:path": {"example.com/foo/types"}},
+				},
+			}
+			bar = &design.UserTypeDefinition{
+				TypeName: "bar",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     design.Object{},
+					Metadata: dslengine.MetadataDefinition{"struct:pkg:path": {"example.com/bar/types"}},
+				},
+			}
+		})
+
+		It("qualifies the type names with the package aliases", func() {
+			Ω(imports.GoTypeRef(foo, nil, 0, false)).Should(Equal("*types.Foo"))
+			Ω(imports.GoTypeName(&design.Array{ElemType: &design.AttributeDefinition{Type: bar}}, nil, 0, false)).Should(Equal("[]*types2.Bar"))
+			Ω(imports.Imports()).Should(HaveLen(3))
+		})
+
+		It("does not qualify the type names of the current package", func() {
+			Ω(imports.GoTypeRefQualified(foo, "example.com/foo/types")).Should(Equal("*Foo"))
+			Ω(imports.GoTypeRefQualified(bar, "example.com/foo/types")).Should(Equal("*types.Bar"))
+			Ω(imports.GoTypeRef(foo, nil, 0, false)).Should(Equal("*types2.Foo"))
+			Ω(imports.Imports()).Should(HaveLen(3))
+		})
+
+		It("does not qualify the type names outside of an import set", func() {
+			Ω(codegen.GoTypeRef(foo, nil, 0, false)).Should(Equal("*Foo"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package codegen
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// jsonAliasKey is the name of the metadata that lists alternate JSON keys of an attribute.
+const jsonAliasKey = "struct:tag:json:alias"
+
+var jsonAliasUnmarshalerT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if jsonAliasUnmarshalerT, err = template.New("jsonAliasUnmarshaler").Parse(jsonAliasUnmarshalerTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// jsonAliasField describes a struct field that may be read from alias JSON keys.
+type jsonAliasField struct {
+	// Name is the name of the struct field.
+	Name string
+	// Key is the canonical JSON key of the field.
+	Key string
+	// Aliases lists the alternate JSON keys in order of precedence.
+	Aliases []string
+}
+
+// GoJSONAliasUnmarshaler produces the Go code of the UnmarshalJSON method of the given object user
+// type if any of its attributes define the "struct:tag:json:alias" metadata, the empty string
+// otherwise. The metadata lists alternate JSON keys the attribute value may be read from, the
+// generated method uses the first alias present when the canonical key is missing. The struct
+// generated for the type has a single field for the attribute so that the canonical key is always
+// used on marshal. Only the attributes of the type itself are considered, not the attributes of
+// inline objects. The generated code requires the "encoding/json" package.
+func GoJSONAliasUnmarshaler(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: JSON alias unmarshaler requires an object user type")
+	}
+	var fields []*jsonAliasField
+	ut.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if aliases := catt.Metadata[jsonAliasKey]; len(aliases) > 0 {
+			fields = append(fields, &jsonAliasField{
+				Name:    goFieldName(n, catt),
+				Key:     jsonKey(n, catt),
+				Aliases: aliases,
+			})
+		}
+		return nil
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Fields": fields,
+	}
+	return RunTemplate(jsonAliasUnmarshalerT, data)
+}
+
+// jsonKey returns the JSON key of the field generated for the attribute with the given name, that
+// is the name given in the "struct:tag:json" metadata if any, the attribute name otherwise.
+func jsonKey(name string, att *design.AttributeDefinition) string {
+	if tag := att.Metadata["struct:tag:json"]; len(tag) > 0 {
+		if key := strings.Split(tag[0], ",")[0]; key != "" {
+			return key
+		}
+	}
+	return name
+}
+
+const jsonAliasUnmarshalerTmpl = `// UnmarshalJSON implements json.Unmarshaler, it reads the renamed fields from their alias keys
+// when the canonical keys are missing.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type plain {{ .Name }}
+	if err := json.Unmarshal(data, (*plain)(ut)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+{{ range .Fields }}	if _, ok := raw[{{ printf "%q" .Key }}]; !ok {
+{{ $field := . }}{{ range $i, $alias := .Aliases }}		{{ if $i }}} else {{ end }}if v, ok := raw[{{ printf "%q" $alias }}]; ok {
+			if err := json.Unmarshal(v, &ut.{{ $field.Name }}); err != nil {
+				return err
+			}
+{{ end }}		}
+	}
+{{ end }}	return nil
+}
+`
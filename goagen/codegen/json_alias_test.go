@@ -0,0 +1,68 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoJSONAliasUnmarshaler", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"struct:tag:json:alias": {"title", "label"}},
+					},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+				},
+			},
+		}
+	})
+
+	It("reads the aliases when the canonical key is missing", func() {
+		Ω(codegen.GoJSONAliasUnmarshaler(ut)).Should(Equal(jsonAliasUnmarshalerCode))
+	})
+
+	It("does not generate struct tags for the aliases", func() {
+		Ω(codegen.GoTypeDef(ut.AttributeDefinition, 0, true, false)).Should(ContainSubstring(
+			"Name *string `json:\"name,omitempty\" xml:\"name,omitempty\"`"))
+	})
+
+	It("returns the empty string if there are no aliases", func() {
+		ut.Type.ToObject()["name"].Metadata = nil
+		Ω(codegen.GoJSONAliasUnmarshaler(ut)).Should(BeEmpty())
+	})
+})
+
+const jsonAliasUnmarshalerCode = `// UnmarshalJSON implements json.Unmarshaler, it reads the renamed fields from their alias keys
+// when the canonical keys are missing.
+func (ut *Bottle) UnmarshalJSON(data []byte) error {
+	type plain Bottle
+	if err := json.Unmarshal(data, (*plain)(ut)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["name"]; !ok {
+		if v, ok := raw["title"]; ok {
+			if err := json.Unmarshal(v, &ut.Name); err != nil {
+				return err
+			}
+		} else if v, ok := raw["label"]; ok {
+			if err := json.Unmarshal(v, &ut.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+`
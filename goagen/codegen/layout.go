@@ -0,0 +1,56 @@
+package codegen
+
+import "github.com/goadesign/goa/design"
+
+// bitmapThreshold is the number of optional primitive fields from which LayoutReport suggests the
+// BitmapFields representation.
+const bitmapThreshold = 4
+
+// LayoutReport describes the memory layout of the struct generated for an object user type with
+// the default PointerFields mode, see AnalyzeLayout.
+type LayoutReport struct {
+	// Fields is the number of fields of the struct.
+	Fields int
+	// PointerFields is the number of fields whose type is a pointer.
+	PointerFields int
+	// OptionalPrimitives is the number of pointer fields holding optional primitive values,
+	// the BitmapFields mode stores these fields as plain values.
+	OptionalPrimitives int
+	// Allocations is the estimated number of heap allocations made to build an instance whose
+	// fields are all set: one for the struct, one per pointer field and one per array or hash
+	// field. The content of the nested objects, arrays and hashes is not counted.
+	Allocations int
+}
+
+// AnalyzeLayout returns the layout report of the struct generated for the given object user type.
+// The report is advisory: it uses the same rules as GoTypeDef to decide which fields are pointers
+// so that generators may warn about wide types that would benefit from another representation.
+func AnalyzeLayout(ut *design.UserTypeDefinition) LayoutReport {
+	if !ut.IsObject() {
+		panic("goa bug: layout analysis requires an object user type")
+	}
+	att := ut.AttributeDefinition
+	report := LayoutReport{Allocations: 1}
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		report.Fields++
+		switch {
+		case isPointerObject(catt.Type, PointerFields):
+			report.PointerFields++
+			report.Allocations++
+		case isOptionalPrimitive(att, n, false):
+			report.PointerFields++
+			report.OptionalPrimitives++
+			report.Allocations++
+		case catt.Type.IsArray() || catt.Type.IsHash():
+			report.Allocations++
+		}
+		return nil
+	})
+	return report
+}
+
+// SuggestsBitmap returns true if the struct has enough optional primitive fields for the
+// BitmapFields representation to save significant allocations, that supports up to 64 of them.
+func (r LayoutReport) SuggestsBitmap() bool {
+	return r.OptionalPrimitives >= bitmapThreshold && r.OptionalPrimitives <= 64
+}
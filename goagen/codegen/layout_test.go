@@ -0,0 +1,56 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AnalyzeLayout", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":     &design.AttributeDefinition{Type: design.Integer},
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					"label": &design.AttributeDefinition{Type: design.Object{
+						"text": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+			},
+		}
+	})
+
+	It("counts the fields and the allocations", func() {
+		report := codegen.AnalyzeLayout(ut)
+		Ω(report).Should(Equal(codegen.LayoutReport{
+			Fields:             5,
+			PointerFields:      3,
+			OptionalPrimitives: 2,
+			Allocations:        5,
+		}))
+		Ω(report.SuggestsBitmap()).Should(BeFalse())
+	})
+
+	It("suggests the bitmap representation for wide optional types", func() {
+		o := ut.Type.ToObject()
+		o["vintage"] = &design.AttributeDefinition{Type: design.Integer}
+		o["color"] = &design.AttributeDefinition{Type: design.String}
+		report := codegen.AnalyzeLayout(ut)
+		Ω(report.OptionalPrimitives).Should(Equal(4))
+		Ω(report.SuggestsBitmap()).Should(BeTrue())
+	})
+
+	It("does not count fields with default values", func() {
+		ut.Type.ToObject()["rating"].DefaultValue = 3
+		Ω(codegen.AnalyzeLayout(ut).OptionalPrimitives).Should(Equal(1))
+	})
+})
@@ -0,0 +1,117 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var mergeT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if mergeT, err = template.New("merge").Parse(mergeTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoMerge produces the Go code of a function that merges an instance of the patch user type into
+// an instance of the base user type, e.g. `MergeBottle(base *Bottle, patch *BottlePatch) *Bottle`.
+// The attributes of patch are typically all optional: the function copies the fields of patch
+// that are set onto base. Arrays and hashes replace the base values wholesale, inline objects are
+// merged recursively and fields whose type is a user type are replaced if the base and patch
+// types are the same and merged by calling the corresponding Merge function otherwise. The
+// function allocates a new base if base is nil and returns base.
+func GoMerge(base, patch *design.UserTypeDefinition) string {
+	if !base.IsObject() || !patch.IsObject() {
+		panic("goa bug: Merge requires object user types")
+	}
+	var buf bytes.Buffer
+	writeMergeFields(&buf, base.AttributeDefinition, patch.AttributeDefinition, "base", "patch", patch.TypeName, 1)
+	data := map[string]interface{}{
+		"Name":   GoTypeName(base, nil, 0, false),
+		"Patch":  GoTypeName(patch, nil, 0, false),
+		"Fields": buf.String(),
+	}
+	return RunTemplate(mergeT, data)
+}
+
+// writeMergeFields writes the code that copies the fields of patch that are set onto base. base
+// and patch must be objects, context is used to build error messages.
+func writeMergeFields(buf *bytes.Buffer, base, patch *design.AttributeDefinition, bvar, pvar, context string, depth int) {
+	bobj := base.Type.ToObject()
+	patch.Type.ToObject().IterateAttributes(func(n string, patt *design.AttributeDefinition) error {
+		batt, ok := bobj[n]
+		if !ok {
+			panic(fmt.Sprintf("attribute %#v of %s is not defined in the base type", n, context))
+		}
+		if batt.Type.Kind() != patt.Type.Kind() {
+			panic(fmt.Sprintf("attribute %#v of %s has type %s in the patch type but %s in the base type",
+				n, context, patt.Type.Name(), batt.Type.Name()))
+		}
+		bfield := fmt.Sprintf("%s.%s", bvar, goFieldName(n, batt))
+		pfield := fmt.Sprintf("%s.%s", pvar, goFieldName(n, patt))
+		pptr := patch.IsPrimitivePointer(n)
+		if patt.Type.IsPrimitive() && !pptr && !isNilablePrimitive(patt.Type) {
+			// the patch field is always set
+			writeLine(buf, depth, "%s = %s", bfield, pfield)
+			return nil
+		}
+		writeLine(buf, depth, "if %s != nil {", pfield)
+		switch {
+		case pptr && base.IsPrimitivePointer(n):
+			v := fmt.Sprintf("v%d", depth)
+			writeLine(buf, depth+1, "%s := *%s", v, pfield)
+			writeLine(buf, depth+1, "%s = &%s", bfield, v)
+		case pptr:
+			writeLine(buf, depth+1, "%s = *%s", bfield, pfield)
+		case patt.Type.Kind() == design.ObjectKind:
+			writeLine(buf, depth+1, "if %s == nil {", bfield)
+			writeLine(buf, depth+2, "%s = new(%s)", bfield, GoTypeDef(batt, depth+2, true, false))
+			writeLine(buf, depth+1, "}")
+			writeMergeFields(buf, batt, patt, bfield, pfield, context+"."+n, depth+1)
+		case isUserType(patt.Type) && userTypeName(patt.Type) != userTypeName(batt.Type):
+			writeLine(buf, depth+1, "%s = Merge%s(%s, %s)", bfield, GoTypeName(batt.Type, nil, 0, false), bfield, pfield)
+		default:
+			writeLine(buf, depth+1, "%s = %s", bfield, pfield)
+		}
+		writeLine(buf, depth, "}")
+		return nil
+	})
+}
+
+// isUserType returns true if dt is a user type or a media type.
+func isUserType(dt design.DataType) bool {
+	switch dt.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		return true
+	}
+	return false
+}
+
+// userTypeName returns the name of the user or media type dt, the empty string if dt is neither.
+func userTypeName(dt design.DataType) string {
+	switch actual := dt.(type) {
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.MediaTypeDefinition:
+		return actual.TypeName
+	}
+	return ""
+}
+
+const mergeTmpl = `// Merge{{ .Name }} copies the fields of patch that are set onto base and returns base, base is
+// allocated if nil.
+func Merge{{ .Name }}(base *{{ .Name }}, patch *{{ .Patch }}) *{{ .Name }} {
+	if base == nil {
+		base = new({{ .Name }})
+	}
+	if patch == nil {
+		return base
+	}
+{{ .Fields }}	return base
+}
+`
@@ -0,0 +1,91 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoMerge", func() {
+	var base, patch *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		base = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					"origin": &design.AttributeDefinition{Type: design.Object{
+						"country": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+		patch = &design.UserTypeDefinition{
+			TypeName: "bottlePatch",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					"origin": &design.AttributeDefinition{Type: design.Object{
+						"country": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+			},
+		}
+	})
+
+	It("copies the fields that are set", func() {
+		Ω(codegen.GoMerge(base, patch)).Should(Equal(mergeCode))
+	})
+
+	It("rejects attributes missing from the base type", func() {
+		patch.Type.ToObject()["color"] = &design.AttributeDefinition{Type: design.String}
+		Ω(func() { codegen.GoMerge(base, patch) }).Should(Panic())
+	})
+
+	It("rejects attributes with different types", func() {
+		patch.Type.ToObject()["rating"] = &design.AttributeDefinition{Type: design.String}
+		Ω(func() { codegen.GoMerge(base, patch) }).Should(Panic())
+	})
+})
+
+const mergeCode = `// MergeBottle copies the fields of patch that are set onto base and returns base, base is
+// allocated if nil.
+func MergeBottle(base *Bottle, patch *BottlePatch) *Bottle {
+	if base == nil {
+		base = new(Bottle)
+	}
+	if patch == nil {
+		return base
+	}
+	if patch.Name != nil {
+		base.Name = *patch.Name
+	}
+	if patch.Origin != nil {
+		if base.Origin == nil {
+			base.Origin = new(struct {
+				Country *string ` + "`" + `json:"country,omitempty" xml:"country,omitempty"` + "`" + `
+			})
+		}
+		if patch.Origin.Country != nil {
+			v2 := *patch.Origin.Country
+			base.Origin.Country = &v2
+		}
+	}
+	if patch.Rating != nil {
+		v1 := *patch.Rating
+		base.Rating = &v1
+	}
+	if patch.Tags != nil {
+		base.Tags = patch.Tags
+	}
+	return base
+}
+`
@@ -0,0 +1,87 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// GoOptionalTypeDef returns the Go code that defines the generic Optional type used by the
+// structs generated with the OptionalFields mode. The code must be generated once per package,
+// it requires Go 1.18 or later and the "encoding/json" import.
+func GoOptionalTypeDef() string {
+	return optionalTypeCode
+}
+
+// GoFieldValue returns the Go code that reads the value of the field generated for the attribute
+// with the given name, target is the Go expression that refers to the field. The code
+// dereferences pointers and calls Get on Optional fields. Optional fields must be checked with
+// IsSet and pointers against nil prior to using the returned expression.
+func GoFieldValue(parent *design.AttributeDefinition, name, target string, private bool, mode StructMode) string {
+	ref := GoFieldTypeRef(parent, name, 0, private, mode)
+	switch {
+	case strings.HasPrefix(ref, "Optional["):
+		return target + ".Get()"
+	case strings.HasPrefix(ref, "*") && parent.Type.ToObject()[name].Type.IsPrimitive():
+		return "*" + target
+	default:
+		return target
+	}
+}
+
+const optionalTypeCode = `// Optional represents a value that may or may not be set.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional set with the given value.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Set sets the optional value.
+func (o *Optional[T]) Set(v T) {
+	o.value = v
+	o.set = true
+}
+
+// Unset clears the optional value.
+func (o *Optional[T]) Unset() {
+	var zero T
+	o.value = zero
+	o.set = false
+}
+
+// Get returns the optional value, the zero value if the optional is not set.
+func (o Optional[T]) Get() T {
+	return o.value
+}
+
+// IsSet returns true if the optional value is set.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// MarshalJSON renders the optional value or null if the optional is not set.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON sets the optional value from the given JSON, null unsets the optional.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		o.Unset()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Set(v)
+	return nil
+}
+`
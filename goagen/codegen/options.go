@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var functionalOptionsT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if functionalOptionsT, err = template.New("functionalOptions").Parse(functionalOptionsTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// optionField describes a struct field initialized by the generated New function.
+type optionField struct {
+	// Name is the name of the struct field.
+	Name string
+	// Param is the name of the parameter of the New function or of the option constructor.
+	Param string
+	// Type is the Go type of the parameter.
+	Type string
+	// Pointer is true if the field is a pointer to the parameter value.
+	Pointer bool
+	// Default is the Go literal of the default value of the field if any.
+	Default string
+}
+
+// GoFunctionalOptions produces the Go code that constructs instances of the given object user
+// type with the functional options pattern: the New function accepts the required fields that
+// have no default value as positional parameters followed by options that set the other fields,
+// e.g. `NewBottle(name string, opts ...BottleOption) *Bottle`. There is one option constructor per
+// optional field named after the field, e.g. "WithRating", so that the options of types generated
+// in the same package must not share field names. Fields with a default value are initialized
+// with it before the options are applied.
+func GoFunctionalOptions(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: functional options require an object user type")
+	}
+	att := ut.AttributeDefinition
+	var params, options []*optionField
+	taken := map[string]bool{"opts": true, "ut": true}
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		f := &optionField{
+			Name:    goFieldName(n, catt),
+			Param:   uniqueName(Goify(n, false), taken),
+			Type:    goValueTypeRef(catt, 0),
+			Pointer: att.IsPrimitivePointer(n),
+		}
+		if lit, ok := GoDefaultLiteral(catt); ok {
+			f.Default = lit
+		}
+		if att.IsRequired(n) && f.Default == "" {
+			params = append(params, f)
+		} else {
+			options = append(options, f)
+		}
+		return nil
+	})
+	data := map[string]interface{}{
+		"Name":    GoTypeName(ut, nil, 0, false),
+		"Params":  params,
+		"Options": options,
+	}
+	return RunTemplate(functionalOptionsT, data)
+}
+
+const functionalOptionsTmpl = `{{ $name := .Name }}// {{ $name }}Option sets an optional field of a {{ $name }}.
+type {{ $name }}Option func(*{{ $name }})
+{{ range .Options }}
+// With{{ .Name }} sets the {{ .Name }} field of the {{ $name }}.
+func With{{ .Name }}({{ .Param }} {{ .Type }}) {{ $name }}Option {
+	return func(ut *{{ $name }}) {
+		ut.{{ .Name }} = {{ if .Pointer }}&{{ end }}{{ .Param }}
+	}
+}
+{{ end }}
+// New{{ $name }} returns a {{ $name }} initialized with the given required fields and default values
+// modified by the given options.
+func New{{ $name }}({{ range .Params }}{{ .Param }} {{ .Type }}, {{ end }}opts ...{{ $name }}Option) *{{ $name }} {
+	ut := &{{ $name }}{
+{{ range .Params }}		{{ .Name }}: {{ .Param }},
+{{ end }}{{ range .Options }}{{ if .Default }}		{{ .Name }}: {{ .Default }},
+{{ end }}{{ end }}	}
+	for _, opt := range opts {
+		opt(ut)
+	}
+	return ut
+}
+`
@@ -0,0 +1,98 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFunctionalOptions", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+					"color":  &design.AttributeDefinition{Type: design.String, DefaultValue: "red"},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name", "color"}},
+			},
+		}
+	})
+
+	It("produces the options and the constructor", func() {
+		Ω(codegen.GoFunctionalOptions(ut)).Should(Equal(bottleOptionsCode))
+	})
+
+	Context("with inline objects", func() {
+		BeforeEach(func() {
+			inline := &design.AttributeDefinition{
+				Type: design.Object{"x": &design.AttributeDefinition{Type: design.Integer}},
+			}
+			ut.Type = design.Object{
+				"nested": inline,
+				"items":  &design.AttributeDefinition{Type: &design.Array{ElemType: inline}},
+			}
+			ut.Validation = &dslengine.ValidationDefinition{Required: []string{"nested"}}
+		})
+
+		It("generates code that compiles", func() {
+			code := "type Bottle " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + codegen.GoFunctionalOptions(ut)
+			Ω(typeCheck(code)).Should(Succeed())
+		})
+	})
+
+	Context("with a primitive user type", func() {
+		BeforeEach(func() {
+			ut.AttributeDefinition = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoFunctionalOptions(ut) }).Should(Panic())
+		})
+	})
+})
+
+const bottleOptionsCode = `// BottleOption sets an optional field of a Bottle.
+type BottleOption func(*Bottle)
+
+// WithColor sets the Color field of the Bottle.
+func WithColor(color string) BottleOption {
+	return func(ut *Bottle) {
+		ut.Color = color
+	}
+}
+
+// WithRating sets the Rating field of the Bottle.
+func WithRating(rating int) BottleOption {
+	return func(ut *Bottle) {
+		ut.Rating = &rating
+	}
+}
+
+// WithTags sets the Tags field of the Bottle.
+func WithTags(tags []string) BottleOption {
+	return func(ut *Bottle) {
+		ut.Tags = tags
+	}
+}
+
+// NewBottle returns a Bottle initialized with the given required fields and default values
+// modified by the given options.
+func NewBottle(name string, opts ...BottleOption) *Bottle {
+	ut := &Bottle{
+		Name: name,
+		Color: "red",
+	}
+	for _, opt := range opts {
+		opt(ut)
+	}
+	return ut
+}
+`
@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// paginatedKey is the name of the metadata that requests the generation of a page wrapper for a
+// user type.
+const paginatedKey = "struct:paginated"
+
+var paginatedT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if paginatedT, err = template.New("paginated").Parse(paginatedTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoPaginatedType produces the Go code that declares the page wrapper of the given user type if
+// it defines the "struct:paginated" metadata, the empty string otherwise. The wrapper holds the
+// items of a page together with the total number of items and the cursor of the next page, e.g.
+// `BottlePage struct { Items []*Bottle; Total int; NextCursor *string }`, and defines the Len and
+// HasNext methods. The items are the elements of array user types and the values of the user type
+// itself otherwise. The wrapper name is the type name suffixed with "Page" and may be overridden
+// with the value of the metadata. GoPaginatedType panics if the wrapper name is the name of a
+// type of the design.
+func GoPaginatedType(ut *design.UserTypeDefinition) string {
+	meta, ok := ut.Metadata[paginatedKey]
+	if !ok {
+		return ""
+	}
+	var elem design.DataType = ut
+	if ut.IsArray() {
+		elem = ut.Type.ToArray().ElemType.Type
+	}
+	name := GoTypeName(ut, nil, 0, false) + "Page"
+	if len(meta) > 0 && meta[0] != "" {
+		name = meta[0]
+	}
+	if other := designTypeNamed(name); other != "" {
+		panic(fmt.Sprintf("page wrapper %s of type %s collides with type %s, use the %s metadata to rename it",
+			name, ut.TypeName, other, paginatedKey))
+	}
+	data := map[string]interface{}{
+		"Name":     name,
+		"ElemType": GoTypeRef(elem, nil, 0, false),
+	}
+	return RunTemplate(paginatedT, data)
+}
+
+// designTypeNamed returns the name of the user type or media type of the design whose Go type name
+// is name, the empty string if there is none.
+func designTypeNamed(name string) string {
+	if design.Design == nil {
+		return ""
+	}
+	for n, ut := range design.Design.Types {
+		if GoTypeName(ut, nil, 0, false) == name {
+			return n
+		}
+	}
+	for _, mt := range design.Design.MediaTypes {
+		if GoTypeName(mt, nil, 0, false) == name {
+			return mt.TypeName
+		}
+	}
+	return ""
+}
+
+const paginatedTmpl = `// {{ .Name }} is a page of {{ .ElemType }} values.
+type {{ .Name }} struct {
+	// Items lists the values of the page.
+	Items []{{ .ElemType }} ` + "`" + `json:"items" xml:"items"` + "`" + `
+	// Total is the total number of values across all pages.
+	Total int ` + "`" + `json:"total" xml:"total"` + "`" + `
+	// NextCursor is the cursor of the next page, nil if this is the last page.
+	NextCursor *string ` + "`" + `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"` + "`" + `
+}
+
+// Len returns the number of values in the page.
+func (p *{{ .Name }}) Len() int {
+	return len(p.Items)
+}
+
+// HasNext returns true if there is a page after p.
+func (p *{{ .Name }}) HasNext() bool {
+	return p.NextCursor != nil
+}
+`
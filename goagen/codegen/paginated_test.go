@@ -0,0 +1,107 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPaginatedType", func() {
+	var meta dslengine.MetadataDefinition
+	var bottle *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		meta = dslengine.MetadataDefinition{"struct:paginated": nil}
+	})
+
+	JustBeforeEach(func() {
+		bottle = &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				Metadata: meta,
+			},
+		}
+	})
+
+	It("produces the page wrapper of object user types", func() {
+		Ω(codegen.GoPaginatedType(bottle)).Should(Equal(bottlePageCode))
+	})
+
+	It("uses the elements of array user types", func() {
+		ids := &design.UserTypeDefinition{
+			TypeName: "IDs",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+				Metadata: meta,
+			},
+		}
+		code := codegen.GoPaginatedType(ids)
+		Ω(code).Should(ContainSubstring("type IDsPage struct {"))
+		Ω(code).Should(ContainSubstring("\tItems []int `json:\"items\" xml:\"items\"`\n"))
+	})
+
+	Context("without the struct:paginated metadata", func() {
+		BeforeEach(func() {
+			meta = nil
+		})
+
+		It("produces nothing", func() {
+			Ω(codegen.GoPaginatedType(bottle)).Should(BeEmpty())
+		})
+	})
+
+	Context("with a wrapper name", func() {
+		BeforeEach(func() {
+			meta = dslengine.MetadataDefinition{"struct:paginated": {"BottleList"}}
+		})
+
+		It("uses the name", func() {
+			Ω(codegen.GoPaginatedType(bottle)).Should(HavePrefix("// BottleList is a page of *Bottle values.\ntype BottleList struct {"))
+		})
+	})
+
+	Context("with a design type of the same name", func() {
+		var api *design.APIDefinition
+
+		BeforeEach(func() {
+			api = design.Design
+			page := &design.UserTypeDefinition{
+				TypeName:            "BottlePage",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+			}
+			design.Design = &design.APIDefinition{Types: map[string]*design.UserTypeDefinition{"BottlePage": page}}
+		})
+
+		AfterEach(func() {
+			design.Design = api
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoPaginatedType(bottle) }).Should(Panic())
+		})
+	})
+})
+
+const bottlePageCode = "// BottlePage is a page of *Bottle values.\n" +
+	"type BottlePage struct {\n" +
+	"\t// Items lists the values of the page.\n" +
+	"\tItems []*Bottle `json:\"items\" xml:\"items\"`\n" +
+	"\t// Total is the total number of values across all pages.\n" +
+	"\tTotal int `json:\"total\" xml:\"total\"`\n" +
+	"\t// NextCursor is the cursor of the next page, nil if this is the last page.\n" +
+	"\tNextCursor *string `json:\"next_cursor,omitempty\" xml:\"next_cursor,omitempty\"`\n" +
+	"}\n" +
+	`
+// Len returns the number of values in the page.
+func (p *BottlePage) Len() int {
+	return len(p.Items)
+}
+
+// HasNext returns true if there is a page after p.
+func (p *BottlePage) HasNext() bool {
+	return p.NextCursor != nil
+}
+`
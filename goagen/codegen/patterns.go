@@ -0,0 +1,108 @@
+package codegen
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var patternVarsT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	fm := template.FuncMap{"patternVar": PatternVarName}
+	if patternVarsT, err = template.New("patternVars").Funcs(fm).Parse(patternVarsTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// PatternVarName returns the name of the package level variable that holds the compiled regular
+// expression of the given pattern validation, e.g. "pattern1a2b3c4d". The name only depends on
+// the pattern so that the validation code and the variable declarations produced by
+// GoPatternVars agree without sharing state.
+func PatternVarName(pattern string) string {
+	h := fnv.New32a()
+	h.Write([]byte(pattern))
+	return fmt.Sprintf("pattern%08x", h.Sum32())
+}
+
+// Patterns returns the sorted distinct patterns of the validations defined on the given attributes
+// and on their children recursively.
+func Patterns(atts ...*design.AttributeDefinition) []string {
+	found := make(map[string]bool)
+	seen := make(map[design.DataType]bool)
+	for _, att := range atts {
+		collectPatterns(att, found, seen)
+	}
+	patterns := make([]string, 0, len(found))
+	for p := range found {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// collectPatterns records the patterns of the validations of att and of its children in found.
+// seen records the user types already visited to break cycles.
+func collectPatterns(att *design.AttributeDefinition, found map[string]bool, seen map[design.DataType]bool) {
+	if att == nil {
+		return
+	}
+	if isUserType(att.Type) {
+		if seen[att.Type] {
+			return
+		}
+		seen[att.Type] = true
+	}
+	if att.Validation != nil && att.Validation.Pattern != "" {
+		found[att.Validation.Pattern] = true
+	}
+	if isUserType(att.Type) {
+		collectPatterns(underlyingAttribute(att), found, seen)
+		return
+	}
+	switch actual := att.Type.(type) {
+	case *design.Array:
+		collectPatterns(actual.ElemType, found, seen)
+	case *design.Hash:
+		collectPatterns(actual.KeyType, found, seen)
+		collectPatterns(actual.ElemType, found, seen)
+	case design.Object:
+		actual.IterateAttributes(func(_ string, catt *design.AttributeDefinition) error {
+			collectPatterns(catt, found, seen)
+			return nil
+		})
+	}
+}
+
+// GoPatternVars produces the Go code that declares the package level variables holding the
+// compiled regular expressions of the given patterns (see Patterns) that the validation code
+// produced by ValidationChecker and RecursiveChecker refers to. The generated code requires the
+// "regexp" package. GoPatternVars panics if two patterns produce the same variable name.
+func GoPatternVars(patterns []string) string {
+	names := make(map[string]string, len(patterns))
+	distinct := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		name := PatternVarName(p)
+		if other, ok := names[name]; ok {
+			if other != p {
+				panic(fmt.Sprintf("patterns %#v and %#v produce the same variable name %s", other, p, name))
+			}
+			continue
+		}
+		names[name] = p
+		distinct = append(distinct, p)
+	}
+	sort.Strings(distinct)
+	return RunTemplate(patternVarsT, distinct)
+}
+
+const patternVarsTmpl = `// Compiled regular expressions of the pattern validations.
+var (
+{{ range . }}	{{ patternVar . }} = regexp.MustCompile({{ printf "%q" . }})
+{{ end }})
+`
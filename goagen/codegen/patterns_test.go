@@ -0,0 +1,59 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Patterns", func() {
+	var att *design.AttributeDefinition
+
+	BeforeEach(func() {
+		pattern := func(p string) *dslengine.ValidationDefinition {
+			return &dslengine.ValidationDefinition{Pattern: p}
+		}
+		ut := &design.UserTypeDefinition{
+			TypeName: "node",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{Type: design.String, Validation: pattern("^[a-z]+$")},
+				},
+			},
+		}
+		ut.Type.ToObject()["next"] = &design.AttributeDefinition{Type: ut}
+		att = &design.AttributeDefinition{
+			Type: design.Object{
+				"name": &design.AttributeDefinition{Type: design.String, Validation: pattern("^[A-Z]")},
+				"node": &design.AttributeDefinition{Type: ut},
+				"tags": &design.AttributeDefinition{
+					Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String, Validation: pattern("^[a-z]+$")}},
+				},
+			},
+		}
+	})
+
+	It("returns the sorted distinct patterns", func() {
+		Ω(codegen.Patterns(att, nil)).Should(Equal([]string{"^[A-Z]", "^[a-z]+$"}))
+	})
+})
+
+var _ = Describe("GoPatternVars", func() {
+	It("declares the pattern variables", func() {
+		code := codegen.GoPatternVars([]string{"^[a-z]+$", ".*", ".*"})
+		Ω(code).Should(Equal(patternVarsCode))
+	})
+
+	It("uses the variable names referred to by the validation code", func() {
+		Ω(codegen.PatternVarName(".*")).Should(Equal("pattern9fd4a0c1"))
+	})
+})
+
+const patternVarsCode = `// Compiled regular expressions of the pattern validations.
+var (
+	pattern9fd4a0c1 = regexp.MustCompile(".*")
+	patternc37a8736 = regexp.MustCompile("^[a-z]+$")
+)
+`
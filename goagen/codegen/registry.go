@@ -0,0 +1,48 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var typeRegistryT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if typeRegistryT, err = template.New("typeRegistry").Parse(typeRegistryTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoTypeRegistry produces the Go code that declares the TypeRegistry variable which maps the Go
+// names of the given user types to functions that return pointers to fresh zero values of the
+// types, e.g. `"Bottle": func() interface{} { return new(Bottle) }`, so that the types can be
+// instantiated by name without reflection. The entries are sorted by name. GoTypeRegistry panics
+// if two distinct types have the same Go name.
+func GoTypeRegistry(types []*design.UserTypeDefinition) string {
+	byName := make(map[string]*design.UserTypeDefinition, len(types))
+	names := make([]string, 0, len(types))
+	for _, ut := range types {
+		name := GoTypeName(ut, nil, 0, false)
+		if other, ok := byName[name]; ok {
+			if other != ut {
+				panic(fmt.Sprintf("types %s and %s are both generated as %s", other.TypeName, ut.TypeName, name))
+			}
+			continue
+		}
+		byName[name] = ut
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return RunTemplate(typeRegistryT, names)
+}
+
+const typeRegistryTmpl = `// TypeRegistry maps the names of the types to functions that return new instances of the types.
+var TypeRegistry = map[string]func() interface{}{
+{{ range . }}	{{ printf "%q" . }}: func() interface{} { return new({{ . }}) },
+{{ end }}}
+`
@@ -0,0 +1,42 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoTypeRegistry", func() {
+	var bottle, account *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		bottle = &design.UserTypeDefinition{
+			TypeName:            "bottle",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+		account = &design.UserTypeDefinition{
+			TypeName:            "Account",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+		}
+	})
+
+	It("maps the sorted type names to constructors", func() {
+		Ω(codegen.GoTypeRegistry([]*design.UserTypeDefinition{bottle, account, bottle})).Should(Equal(typeRegistryCode))
+	})
+
+	It("panics if two types have the same Go name", func() {
+		other := &design.UserTypeDefinition{
+			TypeName:            "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+		Ω(func() { codegen.GoTypeRegistry([]*design.UserTypeDefinition{bottle, other}) }).Should(Panic())
+	})
+})
+
+const typeRegistryCode = `// TypeRegistry maps the names of the types to functions that return new instances of the types.
+var TypeRegistry = map[string]func() interface{}{
+	"Account": func() interface{} { return new(Account) },
+	"Bottle": func() interface{} { return new(Bottle) },
+}
+`
@@ -0,0 +1,179 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+var metadataStructT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if metadataStructT, err = template.New("metadataStruct").Parse(metadataStructTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// metadataSource identifies where the value of a request metadata attribute is read from.
+type metadataSource struct {
+	// Var is the name of the variable holding the values, "header" or "params".
+	Var string
+	// Missing is the name of the goa function that builds the error returned when a required
+	// value is missing.
+	Missing string
+}
+
+var (
+	headerSource = &metadataSource{Var: "header", Missing: "MissingHeaderError"}
+	paramsSource = &metadataSource{Var: "params", Missing: "MissingParamError"}
+)
+
+// GoMetadataStruct produces the Go code of a struct holding the request metadata of the given
+// action together with the function that builds it from the request header and parameters. The
+// metadata consists of the action headers and parameters that define the "metadata" metadata,
+// the struct has one typed field per attribute. Path parameters are read from the parameters as
+// well since goa merges them with the query string parameters. GoMetadataStruct returns the
+// empty string if no attribute defines the "metadata" metadata.
+// The generated code requires the "net/http", "net/url", "strconv" and goa packages and
+// depending on the attribute types the "time", "uuid", "math/big" and "encoding/json" packages.
+func GoMetadataStruct(action *design.ActionDefinition) string {
+	obj := make(design.Object)
+	var required []string
+	sources := make(map[string]*metadataSource)
+	collect := func(att *design.AttributeDefinition, source *metadataSource) {
+		if att == nil {
+			return
+		}
+		att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if _, ok := catt.Metadata["metadata"]; !ok {
+				return nil
+			}
+			if !catt.Type.IsPrimitive() {
+				panic(fmt.Sprintf("metadata attribute %#v of %s must be a primitive", n, action.Context()))
+			}
+			if _, ok := obj[n]; ok {
+				panic(fmt.Sprintf("metadata attribute %#v of %s is defined both as a header and as a parameter", n, action.Context()))
+			}
+			obj[n] = catt
+			sources[n] = source
+			if att.IsRequired(n) {
+				required = append(required, n)
+			}
+			return nil
+		})
+	}
+	collect(action.Headers, headerSource)
+	collect(action.Params, paramsSource)
+	if len(obj) == 0 {
+		return ""
+	}
+
+	att := &design.AttributeDefinition{
+		Type:       obj,
+		Validation: &dslengine.ValidationDefinition{Required: required},
+	}
+	var buf bytes.Buffer
+	obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		writeMetadataField(&buf, att, n, sources[n])
+		return nil
+	})
+	data := map[string]interface{}{
+		"Name":        fmt.Sprintf("%s%sMetadata", Goify(action.Name, true), Goify(action.Parent.Name, true)),
+		"Description": fmt.Sprintf("%s %s", action.Name, action.Parent.Name),
+		"Def":         GoTypeDef(att, 0, false, false),
+		"Fields":      buf.String(),
+	}
+	return RunTemplate(metadataStructT, data)
+}
+
+// writeMetadataField writes the code that initializes the field of res generated for the child
+// attribute of parent with the given name from the values of source.
+func writeMetadataField(buf *bytes.Buffer, parent *design.AttributeDefinition, name string, source *metadataSource) {
+	att := parent.Type.ToObject()[name]
+	field := "res." + goFieldName(name, att)
+	ref := "v"
+	if parent.IsPrimitivePointer(name) {
+		ref = "&v"
+	}
+	writeLine(buf, 1, "if raw := %s.Get(%q); raw != \"\" {", source.Var, name)
+	expected := att.Type.Name()
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		expected = "datetime"
+	case design.UUIDKind:
+		expected = "uuid"
+	case design.DurationKind:
+		expected = "duration"
+	case design.RawJSONKind:
+		expected = "JSON"
+	}
+	invalid := fmt.Sprintf("err = goa.MergeErrors(err, goa.InvalidParamTypeError(%q, raw, %q))", name, expected)
+	switch att.Type.Kind() {
+	case design.StringKind:
+		if ref == "v" {
+			ref = "raw"
+		} else {
+			ref = "&raw"
+		}
+		writeLine(buf, 2, "%s = %s", field, ref)
+	case design.AnyKind:
+		writeLine(buf, 2, "var v interface{} = raw")
+		writeLine(buf, 2, "%s = %s", field, ref)
+	case design.RawJSONKind:
+		writeLine(buf, 2, "if json.Valid([]byte(raw)) {")
+		writeLine(buf, 3, "%s = json.RawMessage(raw)", field)
+		writeLine(buf, 2, "} else {")
+		writeLine(buf, 3, "%s", invalid)
+		writeLine(buf, 2, "}")
+	case design.BigIntKind:
+		writeLine(buf, 2, "if v, ok := new(big.Int).SetString(raw, 10); ok {")
+		writeLine(buf, 3, "%s = v", field)
+		writeLine(buf, 2, "} else {")
+		writeLine(buf, 3, "%s", invalid)
+		writeLine(buf, 2, "}")
+	default:
+		var parse string
+		switch att.Type.Kind() {
+		case design.BooleanKind:
+			parse = "strconv.ParseBool(raw)"
+		case design.IntegerKind:
+			parse = "strconv.Atoi(raw)"
+		case design.NumberKind:
+			parse = "strconv.ParseFloat(raw, 64)"
+		case design.DateTimeKind:
+			parse = "time.Parse(time.RFC3339, raw)"
+		case design.UUIDKind:
+			parse = "uuid.FromString(raw)"
+		case design.DurationKind:
+			parse = "goa.ParseDuration(raw)"
+		default:
+			panic("goa bug: unknown primitive type")
+		}
+		writeLine(buf, 2, "if v, err2 := %s; err2 == nil {", parse)
+		writeLine(buf, 3, "%s = %s", field, ref)
+		writeLine(buf, 2, "} else {")
+		writeLine(buf, 3, "%s", invalid)
+		writeLine(buf, 2, "}")
+	}
+	if parent.IsRequired(name) {
+		writeLine(buf, 1, "} else {")
+		writeLine(buf, 2, "err = goa.MergeErrors(err, goa.%s(%q))", source.Missing, name)
+	}
+	writeLine(buf, 1, "}")
+}
+
+const metadataStructTmpl = `// {{ .Name }} holds the request metadata of the {{ .Description }} action.
+type {{ .Name }} {{ .Def }}
+
+// New{{ .Name }} builds a {{ .Name }} from the request header and parameters.
+func New{{ .Name }}(header http.Header, params url.Values) (*{{ .Name }}, error) {
+	var err error
+	res := new({{ .Name }})
+{{ .Fields }}	return res, err
+}
+`
@@ -0,0 +1,83 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoMetadataStruct", func() {
+	var action *design.ActionDefinition
+	metadata := dslengine.MetadataDefinition{"metadata": nil}
+
+	BeforeEach(func() {
+		action = &design.ActionDefinition{
+			Name:   "show",
+			Parent: &design.ResourceDefinition{Name: "bottle"},
+			Headers: &design.AttributeDefinition{
+				Type: design.Object{
+					"X-Request-ID": &design.AttributeDefinition{Type: design.String, Metadata: metadata},
+					"Accept":       &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"X-Request-ID"}},
+			},
+			Params: &design.AttributeDefinition{
+				Type: design.Object{
+					"limit": &design.AttributeDefinition{Type: design.Integer, Metadata: metadata},
+					"since": &design.AttributeDefinition{Type: design.DateTime},
+				},
+			},
+		}
+	})
+
+	It("generates the struct and its constructor", func() {
+		Ω(codegen.GoMetadataStruct(action)).Should(Equal(metadataStructCode))
+	})
+
+	It("returns the empty string if there is no metadata", func() {
+		action.Headers = nil
+		action.Params = nil
+		Ω(codegen.GoMetadataStruct(action)).Should(BeEmpty())
+	})
+
+	It("rejects non primitive metadata", func() {
+		action.Params.Type.ToObject()["ids"] = &design.AttributeDefinition{
+			Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+			Metadata: metadata,
+		}
+		Ω(func() { codegen.GoMetadataStruct(action) }).Should(Panic())
+	})
+
+	It("rejects metadata defined both as a header and as a parameter", func() {
+		action.Params.Type.ToObject()["X-Request-ID"] = &design.AttributeDefinition{Type: design.String, Metadata: metadata}
+		Ω(func() { codegen.GoMetadataStruct(action) }).Should(Panic())
+	})
+})
+
+const metadataStructCode = `// ShowBottleMetadata holds the request metadata of the show bottle action.
+type ShowBottleMetadata struct {
+	XRequestID string
+	Limit *int
+}
+
+// NewShowBottleMetadata builds a ShowBottleMetadata from the request header and parameters.
+func NewShowBottleMetadata(header http.Header, params url.Values) (*ShowBottleMetadata, error) {
+	var err error
+	res := new(ShowBottleMetadata)
+	if raw := header.Get("X-Request-ID"); raw != "" {
+		res.XRequestID = raw
+	} else {
+		err = goa.MergeErrors(err, goa.MissingHeaderError("X-Request-ID"))
+	}
+	if raw := params.Get("limit"); raw != "" {
+		if v, err2 := strconv.Atoi(raw); err2 == nil {
+			res.Limit = &v
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("limit", raw, "integer"))
+		}
+	}
+	return res, err
+}
+`
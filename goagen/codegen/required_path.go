@@ -0,0 +1,126 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var requiredPathT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if requiredPathT, err = template.New("requiredPath").Parse(requiredPathTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoRequiredPathValidator produces the Go code of the ValidateRequired method of the given object
+// user type. The method returns an error for the first required attribute that is missing, the
+// error message contains the path of the attribute prefixed with the method argument, e.g.
+// "body.address.zip is required". Top level callers pass an empty prefix or the name of the
+// value being validated followed by a dot, the method appends the names of the nested attributes
+// as it recurses into inline objects and calls the ValidateRequired method of nested user types
+// with the extended prefix. Elements of arrays are identified by their index, e.g.
+// "items[2].id" or "grid[2][0].id" for arrays of arrays, elements of hashes are not validated.
+// The generated code requires the "fmt" and "github.com/goadesign/goa" packages.
+func GoRequiredPathValidator(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: required attributes validation requires an object user type")
+	}
+	var buf bytes.Buffer
+	writeRequiredPath(&buf, ut.AttributeDefinition, "ut", "prefix", "", 1)
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Checks": buf.String(),
+	}
+	return RunTemplate(requiredPathT, data)
+}
+
+// writeRequiredPath writes the code that checks the required attributes of the object att stored
+// in target. The error messages are built from the value of the prefix variable followed by path.
+func writeRequiredPath(buf *bytes.Buffer, att *design.AttributeDefinition, target, prefix, path string, depth int) {
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		field := fmt.Sprintf("%s.%s", target, goFieldName(n, catt))
+		fpath := path + n
+		if att.IsRequired(n) {
+			var check string
+			switch {
+			case catt.Type.Kind() == design.StringKind && !att.IsPrimitivePointer(n):
+				check = fmt.Sprintf("%s == \"\"", field)
+			case !catt.Type.IsPrimitive() || isNilablePrimitive(catt.Type) || att.IsPrimitivePointer(n):
+				check = fmt.Sprintf("%s == nil", field)
+			}
+			if check != "" {
+				writeLine(buf, depth, "if %s {", check)
+				writeLine(buf, depth+1, "return goa.ErrInvalidRequest(%s, %s)", pathFormat(fpath, " is required"), prefix)
+				writeLine(buf, depth, "}")
+			}
+		}
+		writeRequiredPathValue(buf, catt, field, prefix, fpath, depth)
+		return nil
+	})
+}
+
+// writeRequiredPathValue writes the code that validates the required attributes of the objects
+// contained in the value of att stored in target.
+func writeRequiredPathValue(buf *bytes.Buffer, att *design.AttributeDefinition, target, prefix, path string, depth int) {
+	switch {
+	case isUserType(att.Type) && att.Type.IsObject():
+		arg := prefix
+		if path != "" {
+			arg = fmt.Sprintf("%s + %s", prefix, strconv.Quote(path+"."))
+		}
+		writeLine(buf, depth, "if err := %s.ValidateRequired(%s); err != nil {", target, arg)
+		writeLine(buf, depth+1, "return err")
+		writeLine(buf, depth, "}")
+	case att.Type.IsObject():
+		var inner bytes.Buffer
+		if path != "" {
+			path += "."
+		}
+		writeRequiredPath(&inner, att, target, prefix, path, depth+1)
+		if inner.Len() > 0 {
+			writeLine(buf, depth, "if %s != nil {", target)
+			buf.Write(inner.Bytes())
+			writeLine(buf, depth, "}")
+		}
+	case att.Type.IsArray():
+		i, e, p := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth), fmt.Sprintf("p%d", depth)
+		elem := att.Type.ToArray().ElemType
+		var inner bytes.Buffer
+		writeRequiredPathValue(&inner, elem, e, p, "", depth+1)
+		if inner.Len() > 0 {
+			// the index of nested arrays follows directly, e.g. "items[0][1].id"
+			suffix := "[%d]."
+			if elem.Type.IsArray() {
+				suffix = "[%d]"
+			}
+			writeLine(buf, depth, "for %s, %s := range %s {", i, e, target)
+			writeLine(buf, depth+1, "%s := fmt.Sprintf(%s, %s, %s)", p, pathFormat(path, suffix), prefix, i)
+			buf.Write(inner.Bytes())
+			writeLine(buf, depth, "}")
+		}
+	}
+}
+
+// pathFormat returns the quoted format string that prints the given path after the prefix
+// followed by suffix, suffix may contain formatting verbs.
+func pathFormat(path, suffix string) string {
+	return strconv.Quote("%s" + strings.Replace(path, "%", "%%", -1) + suffix)
+}
+
+const requiredPathTmpl = `// ValidateRequired returns an error for the first required attribute of the {{ .Name }} instance
+// that is missing, the attribute path in the error message starts with prefix.
+func (ut *{{ .Name }}) ValidateRequired(prefix string) error {
+	if ut == nil {
+		return nil
+	}
+{{ .Checks }}	return nil
+}
+`
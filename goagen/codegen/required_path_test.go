@@ -0,0 +1,100 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoRequiredPathValidator", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		owner := &design.UserTypeDefinition{
+			TypeName: "owner",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:       design.Object{"email": &design.AttributeDefinition{Type: design.String}},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"email"}},
+			},
+		}
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"address": &design.AttributeDefinition{
+						Type:       design.Object{"zip": &design.AttributeDefinition{Type: design.String}},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"zip"}},
+					},
+					"owners": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: owner}}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	It("returns the path of the first missing attribute", func() {
+		Ω(codegen.GoRequiredPathValidator(ut)).Should(Equal(requiredPathCode))
+	})
+
+	It("appends the indices of nested arrays without separator", func() {
+		owner := ut.Type.ToObject()["owners"].Type.ToArray().ElemType
+		ut.Type = design.Object{
+			"grid": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{
+				Type: &design.Array{ElemType: owner},
+			}}},
+		}
+		ut.Validation = nil
+		Ω(codegen.GoRequiredPathValidator(ut)).Should(Equal(nestedArraysRequiredPathCode))
+	})
+
+	It("rejects non object types", func() {
+		ut.Type = design.String
+		Ω(func() { codegen.GoRequiredPathValidator(ut) }).Should(Panic())
+	})
+})
+
+const requiredPathCode = `// ValidateRequired returns an error for the first required attribute of the Bottle instance
+// that is missing, the attribute path in the error message starts with prefix.
+func (ut *Bottle) ValidateRequired(prefix string) error {
+	if ut == nil {
+		return nil
+	}
+	if ut.Address != nil {
+		if ut.Address.Zip == "" {
+			return goa.ErrInvalidRequest("%saddress.zip is required", prefix)
+		}
+	}
+	if ut.Name == "" {
+		return goa.ErrInvalidRequest("%sname is required", prefix)
+	}
+	for i1, e1 := range ut.Owners {
+		p1 := fmt.Sprintf("%sowners[%d].", prefix, i1)
+		if err := e1.ValidateRequired(p1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`
+
+const nestedArraysRequiredPathCode = `// ValidateRequired returns an error for the first required attribute of the Bottle instance
+// that is missing, the attribute path in the error message starts with prefix.
+func (ut *Bottle) ValidateRequired(prefix string) error {
+	if ut == nil {
+		return nil
+	}
+	for i1, e1 := range ut.Grid {
+		p1 := fmt.Sprintf("%sgrid[%d]", prefix, i1)
+		for i2, e2 := range e1 {
+			p2 := fmt.Sprintf("%s[%d].", p1, i2)
+			if err := e2.ValidateRequired(p2); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+`
@@ -0,0 +1,241 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var (
+	serviceInterfaceT *template.Template
+	serviceMockT      *template.Template
+	endpointAdapterT  *template.Template
+)
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if serviceInterfaceT, err = template.New("serviceInterface").Parse(serviceInterfaceTmpl); err != nil {
+		panic(err)
+	}
+	if serviceMockT, err = template.New("serviceMock").Parse(serviceMockTmpl); err != nil {
+		panic(err)
+	}
+	if endpointAdapterT, err = template.New("endpointAdapter").Parse(endpointAdapterTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// serviceMethod describes a method of the interface generated for a resource.
+type serviceMethod struct {
+	// Name is the name of the method.
+	Name string
+	// Comment is the indented comment built from the action description if any.
+	Comment string
+	// Signature is the method signature, see GoMethodSignature.
+	Signature string
+	// FuncType is the type of a function with the method signature.
+	FuncType string
+	// Args lists the names of the method arguments.
+	Args string
+	// HasPayload is true if the method accepts a payload.
+	HasPayload bool
+	// Payload is the Go type of the method payload if any.
+	Payload string
+	// Result is the Go type of the method result if any.
+	Result string
+}
+
+// GoServiceInterface produces the Go code of the interface that groups the methods of the given
+// resource, e.g. "BottleService". The interface has one method per action, the method accepts the
+// action payload if any and returns the type of the first successful response if any, see
+// GoMethodSignature. The generated code requires the "context" package.
+func GoServiceInterface(res *design.ResourceDefinition) string {
+	data := map[string]interface{}{
+		"Name":     Goify(res.Name, true) + "Service",
+		"Resource": res.Name,
+		"Methods":  serviceMethods(res),
+	}
+	return RunTemplate(serviceInterfaceT, data)
+}
+
+// GoServiceMock produces the Go code of a mock implementation of the interface produced by
+// GoServiceInterface for the given resource, e.g. "BottleServiceMock". The mock struct has one
+// function field per method, e.g. "ShowFunc", that implements the method and records the calls
+// made to the methods in order. Methods whose function field is not set return an error. The
+// generated code requires the "context", "errors" and "sync" packages.
+func GoServiceMock(res *design.ResourceDefinition) string {
+	name := Goify(res.Name, true) + "Service"
+	data := map[string]interface{}{
+		"Name":    name,
+		"Mock":    name + "Mock",
+		"Methods": serviceMethods(res),
+	}
+	return RunTemplate(serviceMockT, data)
+}
+
+// GoEndpointAdapter produces the Go code of the function that exposes the method generated for the
+// given action by GoServiceInterface as a goa.Endpoint, e.g.
+// `func MakeShowBottleEndpoint(svc BottleService) goa.Endpoint`. The endpoint asserts that the
+// request is of the payload type of the method and returns an error otherwise, it returns the
+// method result if any and nil otherwise. The generated code requires the "context", "fmt" and
+// "github.com/goadesign/goa" packages.
+func GoEndpointAdapter(a *design.ActionDefinition) string {
+	res := Goify(a.Parent.Name, true)
+	m := newServiceMethod(a)
+	data := map[string]interface{}{
+		"Name":    "Make" + m.Name + res + "Endpoint",
+		"Service": res + "Service",
+		"Method":  m,
+	}
+	return RunTemplate(endpointAdapterT, data)
+}
+
+// GoStatusConstants produces the Go code that declares one constant per error response of the given
+// action whose value is the HTTP status of the response, e.g. `StatusShowBottleNotFound = 404`
+// for the "NotFound" response of the "show" action of the "bottle" resource. Error responses are
+// the responses whose status is 400 or more, the constants are sorted by response name. The
+// function returns the empty string if the action defines no error response.
+func GoStatusConstants(a *design.ActionDefinition) string {
+	prefix := "Status" + GoifyMethod(a.Name)
+	if a.Parent != nil {
+		prefix += Goify(a.Parent.Name, true)
+	}
+	var buf bytes.Buffer
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		if r.Status >= 400 {
+			writeLine(&buf, 1, "%s%s = %d", prefix, Goify(r.Name, true), r.Status)
+		}
+		return nil
+	})
+	if buf.Len() == 0 {
+		return ""
+	}
+	return "// HTTP statuses of the error responses of " + a.Name + ".\nconst (\n" + buf.String() + ")\n"
+}
+
+// serviceMethods returns the methods of the interface generated for res sorted by name.
+func serviceMethods(res *design.ResourceDefinition) []*serviceMethod {
+	var methods []*serviceMethod
+	res.IterateActions(func(a *design.ActionDefinition) error {
+		methods = append(methods, newServiceMethod(a))
+		return nil
+	})
+	return methods
+}
+
+// newServiceMethod returns the method of the interface generated for the resource of a.
+func newServiceMethod(a *design.ActionDefinition) *serviceMethod {
+	name := GoifyMethod(a.Name)
+	var payload design.DataType
+	if a.Payload != nil {
+		payload = a.Payload
+	}
+	result := actionResult(a)
+	sig := GoMethodSignature(a.Name, payload, result)
+	m := &serviceMethod{
+		Name:       name,
+		Signature:  sig,
+		FuncType:   "func" + strings.TrimPrefix(sig, name),
+		Args:       "ctx",
+		HasPayload: payload != nil,
+	}
+	if a.Description != "" {
+		m.Comment = "\t" + strings.Replace(Comment(a.Description), "\n", "\n\t", -1) + "\n"
+	}
+	if m.HasPayload {
+		m.Args += ", p"
+		m.Payload = GoTypeRef(payload, nil, 0, false)
+	}
+	if result != nil {
+		m.Result = GoTypeRef(result, nil, 0, false)
+	}
+	return m
+}
+
+// actionResult returns the type of the body of the first successful response of the action in
+// order of status, nil if there is none.
+func actionResult(a *design.ActionDefinition) design.DataType {
+	var (
+		result design.DataType
+		status int
+	)
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		if r.Status < 200 || r.Status >= 300 || (result != nil && r.Status >= status) {
+			return nil
+		}
+		var t design.DataType
+		if r.Type != nil {
+			t = r.Type
+		} else if r.MediaType != "" && design.Design != nil {
+			if mt := design.Design.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+				t = mt
+			}
+		}
+		if t != nil {
+			result, status = t, r.Status
+		}
+		return nil
+	})
+	return result
+}
+
+const (
+	serviceInterfaceTmpl = `// {{ .Name }} groups the methods of the {{ .Resource }} resource.
+type {{ .Name }} interface {
+{{ range .Methods }}{{ .Comment }}	{{ .Signature }}
+{{ end }}}
+`
+
+	serviceMockTmpl = `// {{ .Mock }}Call records a call made to a {{ .Mock }} method.
+type {{ .Mock }}Call struct {
+	// Method is the name of the method.
+	Method string
+	// Payload is the payload given to the method if any.
+	Payload interface{}
+}
+
+// {{ .Mock }} is a mock implementation of {{ .Name }}. Each method records the call
+// in Calls and calls the corresponding function field.
+type {{ .Mock }} struct {
+{{ range .Methods }}	// {{ .Name }}Func implements {{ .Name }}.
+	{{ .Name }}Func {{ .FuncType }}
+{{ end }}	// Calls lists the calls made to the mock methods in order.
+	Calls []*{{ .Mock }}Call
+
+	mu sync.Mutex
+}
+{{ range .Methods }}
+// {{ .Name }} calls {{ .Name }}Func, it returns an error if {{ .Name }}Func is nil.
+func (m *{{ $.Mock }}) {{ .Signature }} {
+	m.record("{{ .Name }}", {{ if .HasPayload }}p{{ else }}nil{{ end }})
+	if m.{{ .Name }}Func == nil {
+{{ if .Result }}		var res {{ .Result }}
+		return res, errors.New("{{ $.Mock }}: {{ .Name }}Func is not set")
+{{ else }}		return errors.New("{{ $.Mock }}: {{ .Name }}Func is not set")
+{{ end }}	}
+	return m.{{ .Name }}Func({{ .Args }})
+}
+{{ end }}
+// record records a call to the given method.
+func (m *{{ .Mock }}) record(method string, payload interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, &{{ .Mock }}Call{Method: method, Payload: payload})
+}
+`
+	endpointAdapterTmpl = `// {{ .Name }} returns the endpoint that calls the {{ .Method.Name }} method of svc.
+func {{ .Name }}(svc {{ .Service }}) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+{{ if .Method.HasPayload }}		p, ok := req.({{ .Method.Payload }})
+		if !ok {
+			return nil, fmt.Errorf("invalid {{ .Method.Name }} payload type %T, expected {{ .Method.Payload }}", req)
+		}
+{{ end }}{{ if .Method.Result }}		return svc.{{ .Method.Name }}({{ .Method.Args }})
+{{ else }}		return nil, svc.{{ .Method.Name }}({{ .Method.Args }})
+{{ end }}	}
+}
+`
+)
@@ -0,0 +1,168 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("service interface", func() {
+	var res *design.ResourceDefinition
+
+	BeforeEach(func() {
+		bottle := &design.UserTypeDefinition{
+			TypeName:            "bottle",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+		payload := &design.UserTypeDefinition{
+			TypeName:            "ShowPayload",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+		res = &design.ResourceDefinition{
+			Name: "bottle",
+			Actions: map[string]*design.ActionDefinition{
+				"show": {
+					Name:        "show",
+					Description: "Show a bottle.",
+					Payload:     payload,
+					Responses: map[string]*design.ResponseDefinition{
+						"OK":       {Name: "OK", Status: 200, Type: bottle},
+						"NotFound": {Name: "NotFound", Status: 404},
+					},
+				},
+				"delete": {
+					Name:      "delete",
+					Responses: map[string]*design.ResponseDefinition{"NoContent": {Name: "NoContent", Status: 204}},
+				},
+			},
+		}
+	})
+
+	Describe("GoServiceInterface", func() {
+		It("produces one method per action", func() {
+			Ω(codegen.GoServiceInterface(res)).Should(Equal(serviceInterfaceCode))
+		})
+	})
+
+	Describe("GoServiceMock", func() {
+		It("produces the mock implementation", func() {
+			Ω(codegen.GoServiceMock(res)).Should(Equal(serviceMockCode))
+		})
+	})
+
+	Describe("GoStatusConstants", func() {
+		BeforeEach(func() {
+			for _, a := range res.Actions {
+				a.Parent = res
+			}
+			res.Actions["show"].Responses["BadRequest"] = &design.ResponseDefinition{Name: "BadRequest", Status: 400}
+		})
+
+		It("produces one constant per error response", func() {
+			Ω(codegen.GoStatusConstants(res.Actions["show"])).Should(Equal("// HTTP statuses of the error responses of show.\n" +
+				"const (\n" +
+				"\tStatusShowBottleBadRequest = 400\n" +
+				"\tStatusShowBottleNotFound = 404\n" +
+				")\n"))
+		})
+
+		It("produces nothing for actions without error responses", func() {
+			Ω(codegen.GoStatusConstants(res.Actions["delete"])).Should(BeEmpty())
+		})
+	})
+
+	Describe("GoEndpointAdapter", func() {
+		BeforeEach(func() {
+			for _, a := range res.Actions {
+				a.Parent = res
+			}
+		})
+
+		It("asserts the payload type and returns the result", func() {
+			Ω(codegen.GoEndpointAdapter(res.Actions["show"])).Should(Equal(showEndpointCode))
+		})
+
+		It("returns nil for methods without result", func() {
+			Ω(codegen.GoEndpointAdapter(res.Actions["delete"])).Should(Equal(deleteEndpointCode))
+		})
+	})
+})
+
+const (
+	showEndpointCode = `// MakeShowBottleEndpoint returns the endpoint that calls the Show method of svc.
+func MakeShowBottleEndpoint(svc BottleService) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		p, ok := req.(*ShowPayload)
+		if !ok {
+			return nil, fmt.Errorf("invalid Show payload type %T, expected *ShowPayload", req)
+		}
+		return svc.Show(ctx, p)
+	}
+}
+`
+
+	deleteEndpointCode = `// MakeDeleteBottleEndpoint returns the endpoint that calls the Delete method of svc.
+func MakeDeleteBottleEndpoint(svc BottleService) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, svc.Delete(ctx)
+	}
+}
+`
+
+	serviceInterfaceCode = `// BottleService groups the methods of the bottle resource.
+type BottleService interface {
+	Delete(ctx context.Context) error
+	// Show a bottle.
+	Show(ctx context.Context, p *ShowPayload) (*Bottle, error)
+}
+`
+
+	serviceMockCode = `// BottleServiceMockCall records a call made to a BottleServiceMock method.
+type BottleServiceMockCall struct {
+	// Method is the name of the method.
+	Method string
+	// Payload is the payload given to the method if any.
+	Payload interface{}
+}
+
+// BottleServiceMock is a mock implementation of BottleService. Each method records the call
+// in Calls and calls the corresponding function field.
+type BottleServiceMock struct {
+	// DeleteFunc implements Delete.
+	DeleteFunc func(ctx context.Context) error
+	// ShowFunc implements Show.
+	ShowFunc func(ctx context.Context, p *ShowPayload) (*Bottle, error)
+	// Calls lists the calls made to the mock methods in order.
+	Calls []*BottleServiceMockCall
+
+	mu sync.Mutex
+}
+
+// Delete calls DeleteFunc, it returns an error if DeleteFunc is nil.
+func (m *BottleServiceMock) Delete(ctx context.Context) error {
+	m.record("Delete", nil)
+	if m.DeleteFunc == nil {
+		return errors.New("BottleServiceMock: DeleteFunc is not set")
+	}
+	return m.DeleteFunc(ctx)
+}
+
+// Show calls ShowFunc, it returns an error if ShowFunc is nil.
+func (m *BottleServiceMock) Show(ctx context.Context, p *ShowPayload) (*Bottle, error) {
+	m.record("Show", p)
+	if m.ShowFunc == nil {
+		var res *Bottle
+		return res, errors.New("BottleServiceMock: ShowFunc is not set")
+	}
+	return m.ShowFunc(ctx, p)
+}
+
+// record records a call to the given method.
+func (m *BottleServiceMock) record(method string, payload interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, &BottleServiceMockCall{Method: method, Payload: payload})
+}
+`
+)
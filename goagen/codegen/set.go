@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var setT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if setT, err = template.New("set").Parse(setTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoSetType produces the Go code that declares the set type of the given array user type whose
+// items are unique (see the UniqueItems DSL), e.g. "type Tags map[string]struct{}", together with
+// a constructor and the Add, Has, Remove and Slice methods. The set is encoded in JSON as an array
+// of its values so that it can be used in place of the array, the generated code requires the
+// "encoding/json" package. GoSetType panics if the items of the
+// array cannot be used as map keys, that is if they are not comparable (see IsComparable) or if
+// they are objects which are generated as pointers.
+func GoSetType(ut *design.UserTypeDefinition) string {
+	if !ut.IsArray() || ut.Validation == nil || !ut.Validation.UniqueItems {
+		panic("goa bug: set types require an array user type with unique items")
+	}
+	elem := ut.Type.ToArray().ElemType
+	if !IsComparable(elem.Type) || elem.Type.IsObject() {
+		panic(fmt.Sprintf("the items of %s cannot be used as set keys, their type %s is not comparable",
+			ut.TypeName, elem.Type.Name()))
+	}
+	data := map[string]interface{}{
+		"Name":     GoTypeName(ut, nil, 0, false),
+		"ElemType": GoTypeRef(elem.Type, elem.AllRequired(), 0, false),
+	}
+	return RunTemplate(setT, data)
+}
+
+const setTmpl = `// {{ .Name }} is a set of {{ .ElemType }} values.
+type {{ .Name }} map[{{ .ElemType }}]struct{}
+
+// New{{ .Name }} returns a set that contains the given values.
+func New{{ .Name }}(vs ...{{ .ElemType }}) {{ .Name }} {
+	s := make({{ .Name }}, len(vs))
+	for _, v := range vs {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add adds v to the set.
+func (s {{ .Name }}) Add(v {{ .ElemType }}) {
+	s[v] = struct{}{}
+}
+
+// Has returns true if the set contains v.
+func (s {{ .Name }}) Has(v {{ .ElemType }}) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// Remove removes v from the set.
+func (s {{ .Name }}) Remove(v {{ .ElemType }}) {
+	delete(s, v)
+}
+
+// Slice returns the values of the set in no particular order.
+func (s {{ .Name }}) Slice() []{{ .ElemType }} {
+	res := make([]{{ .ElemType }}, 0, len(s))
+	for v := range s {
+		res = append(res, v)
+	}
+	return res
+}
+
+// MarshalJSON encodes the set as a JSON array of its values in no particular order.
+func (s {{ .Name }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON decodes the set from a JSON array, duplicate values are only added once.
+func (s *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var vs []{{ .ElemType }}
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return err
+	}
+	*s = New{{ .Name }}(vs...)
+	return nil
+}
+`
@@ -0,0 +1,107 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoSetType", func() {
+	var elem design.DataType
+	var validation *dslengine.ValidationDefinition
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		elem = design.String
+		validation = &dslengine.ValidationDefinition{UniqueItems: true}
+	})
+
+	JustBeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "tags",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:       &design.Array{ElemType: &design.AttributeDefinition{Type: elem}},
+				Validation: validation,
+			},
+		}
+	})
+
+	It("produces the set type and its methods", func() {
+		Ω(codegen.GoSetType(ut)).Should(Equal(tagsSetCode))
+	})
+
+	Context("without unique items validation", func() {
+		BeforeEach(func() {
+			validation = nil
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoSetType(ut) }).Should(Panic())
+		})
+	})
+
+	Context("with items that are not comparable", func() {
+		BeforeEach(func() {
+			elem = &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}
+		})
+
+		It("panics", func() {
+			Ω(func() { codegen.GoSetType(ut) }).Should(Panic())
+		})
+	})
+})
+
+const tagsSetCode = `// Tags is a set of string values.
+type Tags map[string]struct{}
+
+// NewTags returns a set that contains the given values.
+func NewTags(vs ...string) Tags {
+	s := make(Tags, len(vs))
+	for _, v := range vs {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add adds v to the set.
+func (s Tags) Add(v string) {
+	s[v] = struct{}{}
+}
+
+// Has returns true if the set contains v.
+func (s Tags) Has(v string) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// Remove removes v from the set.
+func (s Tags) Remove(v string) {
+	delete(s, v)
+}
+
+// Slice returns the values of the set in no particular order.
+func (s Tags) Slice() []string {
+	res := make([]string, 0, len(s))
+	for v := range s {
+		res = append(res, v)
+	}
+	return res
+}
+
+// MarshalJSON encodes the set as a JSON array of its values in no particular order.
+func (s Tags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON decodes the set from a JSON array, duplicate values are only added once.
+func (s *Tags) UnmarshalJSON(data []byte) error {
+	var vs []string
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return err
+	}
+	*s = NewTags(vs...)
+	return nil
+}
+`
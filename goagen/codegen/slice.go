@@ -0,0 +1,32 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+)
+
+// capacityKey is the name of the metadata that gives the capacity of the slices allocated for an
+// array attribute.
+const capacityKey = "struct:field:capacity"
+
+// GoMakeSlice produces the Go code that allocates an empty slice for the given array attribute.
+// The capacity of the slice is given by the "struct:field:capacity" metadata if any, e.g.
+// "make([]string, 0, 10)", the slice has no capacity otherwise. Design validation checks that the
+// capacity is a non-negative integer. GoMakeSlice panics if the attribute is not an array.
+func GoMakeSlice(att *design.AttributeDefinition) string {
+	if !att.Type.IsArray() {
+		panic("goa bug: GoMakeSlice requires an array attribute")
+	}
+	typ := GoTypeRef(att.Type, nil, 0, false)
+	hint, ok := att.Metadata[capacityKey]
+	if !ok || len(hint) == 0 {
+		return fmt.Sprintf("make(%s, 0)", typ)
+	}
+	capacity, err := strconv.Atoi(hint[0])
+	if err != nil || capacity < 0 {
+		panic(fmt.Sprintf("goa bug: invalid %s metadata %#v", capacityKey, hint[0])) // bug
+	}
+	return fmt.Sprintf("make(%s, 0, %d)", typ, capacity)
+}
@@ -0,0 +1,33 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoMakeSlice", func() {
+	var att *design.AttributeDefinition
+
+	BeforeEach(func() {
+		att = &design.AttributeDefinition{
+			Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+		}
+	})
+
+	It("allocates an empty slice", func() {
+		Ω(codegen.GoMakeSlice(att)).Should(Equal("make([]string, 0)"))
+	})
+
+	It("uses the capacity hint", func() {
+		att.Metadata = dslengine.MetadataDefinition{"struct:field:capacity": {"16"}}
+		Ω(codegen.GoMakeSlice(att)).Should(Equal("make([]string, 0, 16)"))
+	})
+
+	It("rejects non array attributes", func() {
+		att.Type = design.String
+		Ω(func() { codegen.GoMakeSlice(att) }).Should(Panic())
+	})
+})
@@ -0,0 +1,93 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var sqlValuerT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if sqlValuerT, err = template.New("sqlValuer").Parse(sqlValuerTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// GoSQLValuer produces the Go code of the Scan and Value methods that implement the sql.Scanner
+// and driver.Valuer interfaces for the given user type so that its values can be stored in a SQL
+// column. String user types are stored as strings and object user types as their JSON
+// representation. NULL is scanned as the zero value and nil object pointers are stored as NULL.
+// The imports required by the generated code are returned by SQLValuerImports.
+func GoSQLValuer(ut *design.UserTypeDefinition) string {
+	kind := ut.Type.Kind()
+	if kind != design.StringKind && kind != design.ObjectKind {
+		panic("goa bug: SQL methods require a string or object user type")
+	}
+	data := map[string]interface{}{
+		"Name":   GoTypeName(ut, nil, 0, false),
+		"Object": kind == design.ObjectKind,
+	}
+	return RunTemplate(sqlValuerT, data)
+}
+
+// SQLValuerImports returns the imports required by the code produced by GoSQLValuer for the
+// given user type.
+func SQLValuerImports(ut *design.UserTypeDefinition) []*ImportSpec {
+	imports := []*ImportSpec{SimpleImport("database/sql/driver"), SimpleImport("fmt")}
+	if ut.Type.Kind() == design.ObjectKind {
+		imports = append(imports, SimpleImport("encoding/json"))
+	}
+	return imports
+}
+
+const sqlValuerTmpl = `{{ if .Object }}// Scan implements the sql.Scanner interface, the column holds the JSON representation of the value.
+func (ut *{{ .Name }}) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*ut = {{ .Name }}{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into {{ .Name }}", src)
+	}
+	return json.Unmarshal(data, ut)
+}
+
+// Value implements the driver.Valuer interface, the value is stored as JSON and nil as NULL.
+func (ut *{{ .Name }}) Value() (driver.Value, error) {
+	if ut == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(ut)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+{{ else }}// Scan implements the sql.Scanner interface.
+func (ut *{{ .Name }}) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*ut = ""
+	case string:
+		*ut = {{ .Name }}(v)
+	case []byte:
+		*ut = {{ .Name }}(v)
+	default:
+		return fmt.Errorf("cannot scan %T into {{ .Name }}", src)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (ut {{ .Name }}) Value() (driver.Value, error) {
+	return string(ut), nil
+}
+{{ end }}`
@@ -0,0 +1,74 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoSQLValuer", func() {
+	var ut *design.UserTypeDefinition
+
+	Context("with a string user type", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName:            "status",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+			}
+		})
+
+		It("stores the string", func() {
+			Ω(codegen.GoSQLValuer(ut)).Should(Equal(stringSQLValuerCode))
+			Ω(codegen.SQLValuerImports(ut)).Should(HaveLen(2))
+		})
+	})
+
+	Context("with an object user type", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "bottle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				},
+			}
+		})
+
+		It("stores JSON", func() {
+			code := codegen.GoSQLValuer(ut)
+			Ω(code).Should(ContainSubstring("func (ut *Bottle) Scan(src interface{}) error {"))
+			Ω(code).Should(ContainSubstring("return json.Unmarshal(data, ut)"))
+			Ω(code).Should(ContainSubstring("func (ut *Bottle) Value() (driver.Value, error) {"))
+			Ω(codegen.SQLValuerImports(ut)).Should(ContainElement(codegen.SimpleImport("encoding/json")))
+		})
+	})
+
+	It("rejects other types", func() {
+		ut = &design.UserTypeDefinition{
+			TypeName:            "rating",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Integer},
+		}
+		Ω(func() { codegen.GoSQLValuer(ut) }).Should(Panic())
+	})
+})
+
+const stringSQLValuerCode = `// Scan implements the sql.Scanner interface.
+func (ut *Status) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*ut = ""
+	case string:
+		*ut = Status(v)
+	case []byte:
+		*ut = Status(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Status", src)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (ut Status) Value() (driver.Value, error) {
+	return string(ut), nil
+}
+`
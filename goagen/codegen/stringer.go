@@ -0,0 +1,129 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var (
+	stringMethodT          *template.Template
+	primitiveStringMethodT *template.Template
+)
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if stringMethodT, err = template.New("stringMethod").Parse(stringMethodTmpl); err != nil {
+		panic(err)
+	}
+	if primitiveStringMethodT, err = template.New("primitiveStringMethod").Parse(primitiveStringMethodTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// stringField describes a struct field printed by the generated String method.
+type stringField struct {
+	// Name is the name of the struct field.
+	Name string
+	// Pointer is true if the field is a pointer to a primitive value.
+	Pointer bool
+}
+
+// GoStringMethod produces the Go code of the String method of the given object or primitive user
+// type. If the object user type defines the "struct:display" metadata then the method returns the
+// value of the attribute it names (design validation checks that it exists), otherwise it returns
+// a compact representation of all the fields, e.g. `Bottle{Name: foo, Rating: <nil>}`. Nil
+// pointers are printed as "<nil>" so that the method never panics. The generated code requires the "fmt" and "strings" packages.
+// The String method of a primitive user type returns the underlying value: strings are returned
+// as is, booleans as "true" or "false", integers in base 10 and numbers using the %g format of the
+// fmt package, e.g. "type Status string" produces "return string(ut)". The code generated for
+// boolean, integer and number user types requires the "strconv" package. Any and big integer
+// user types are not supported.
+func GoStringMethod(ut *design.UserTypeDefinition) string {
+	if ut.Type.IsPrimitive() {
+		return goPrimitiveStringMethod(ut)
+	}
+	if !ut.IsObject() {
+		panic("goa bug: String method requires an object or primitive user type")
+	}
+	att := ut.AttributeDefinition
+	o := att.Type.ToObject()
+	data := map[string]interface{}{"Name": GoTypeName(ut, nil, 0, false)}
+	if display, ok := ut.Metadata["struct:display"]; ok && len(display) > 0 {
+		field, ok := o[display[0]]
+		if !ok {
+			panic(fmt.Sprintf("goa bug: unknown struct:display attribute %#v", display[0])) // bug
+		}
+		data["Display"] = newStringField(att, display[0], field)
+	} else {
+		var fields []*stringField
+		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			fields = append(fields, newStringField(att, n, catt))
+			return nil
+		})
+		data["Fields"] = fields
+	}
+	return RunTemplate(stringMethodT, data)
+}
+
+// goPrimitiveStringMethod produces the String method of the given primitive user type.
+func goPrimitiveStringMethod(ut *design.UserTypeDefinition) string {
+	var expr string
+	switch ut.Type.Kind() {
+	case design.BooleanKind:
+		expr = "strconv.FormatBool(bool(ut))"
+	case design.IntegerKind:
+		expr = "strconv.Itoa(int(ut))"
+	case design.NumberKind:
+		expr = "strconv.FormatFloat(float64(ut), 'g', -1, 64)"
+	case design.StringKind:
+		expr = "string(ut)"
+	case design.DateTimeKind, design.UUIDKind, design.DurationKind:
+		expr = GoNativeType(ut.Type) + "(ut).String()"
+	default:
+		panic(fmt.Sprintf("String method of %s cannot be generated for %s values", ut.TypeName, ut.Type.Name()))
+	}
+	data := map[string]interface{}{
+		"Name": GoTypeName(ut, nil, 0, false),
+		"Expr": expr,
+	}
+	return RunTemplate(primitiveStringMethodT, data)
+}
+
+// newStringField builds the description of the field generated for the child attribute of parent
+// with the given name.
+func newStringField(parent *design.AttributeDefinition, name string, field *design.AttributeDefinition) *stringField {
+	return &stringField{
+		Name:    goFieldName(name, field),
+		Pointer: !field.Type.IsObject() && parent.IsPrimitivePointer(name),
+	}
+}
+
+const primitiveStringMethodTmpl = `// String returns the underlying value of the {{ .Name }} instance.
+func (ut {{ .Name }}) String() string {
+	return {{ .Expr }}
+}
+`
+
+const stringMethodTmpl = `// String returns a string representation of the {{ .Name }} instance.
+func (ut *{{ .Name }}) String() string {
+	if ut == nil {
+		return "<nil>"
+	}
+{{ with .Display }}{{ if .Pointer }}	if ut.{{ .Name }} == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", *ut.{{ .Name }})
+{{ else }}	return fmt.Sprintf("%v", ut.{{ .Name }})
+{{ end }}{{ else }}	fields := make([]string, 0, {{ len .Fields }})
+{{ range .Fields }}{{ if .Pointer }}	if ut.{{ .Name }} == nil {
+		fields = append(fields, "{{ .Name }}: <nil>")
+	} else {
+		fields = append(fields, fmt.Sprintf("{{ .Name }}: %v", *ut.{{ .Name }}))
+	}
+{{ else }}	fields = append(fields, fmt.Sprintf("{{ .Name }}: %v", ut.{{ .Name }}))
+{{ end }}{{ end }}	return "{{ .Name }}{" + strings.Join(fields, ", ") + "}"
+{{ end }}}
+`
@@ -0,0 +1,99 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoStringMethod", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	Context("given an object with no display field", func() {
+		It("prints all the fields", func() {
+			Ω(codegen.GoStringMethod(ut)).Should(Equal(stringMethodCode))
+		})
+	})
+
+	Context("given an object with a display field", func() {
+		BeforeEach(func() {
+			ut.Metadata = dslengine.MetadataDefinition{"struct:display": {"rating"}}
+		})
+
+		It("prints the display field", func() {
+			Ω(codegen.GoStringMethod(ut)).Should(Equal(displayStringMethodCode))
+		})
+	})
+
+	Context("given primitive user types", func() {
+		primitive := func(t design.DataType) *design.UserTypeDefinition {
+			return &design.UserTypeDefinition{
+				TypeName:            "status",
+				AttributeDefinition: &design.AttributeDefinition{Type: t},
+			}
+		}
+
+		It("returns the underlying value", func() {
+			Ω(codegen.GoStringMethod(primitive(design.String))).Should(Equal(primitiveStringMethodCode("string(ut)")))
+			Ω(codegen.GoStringMethod(primitive(design.Boolean))).Should(Equal(primitiveStringMethodCode("strconv.FormatBool(bool(ut))")))
+			Ω(codegen.GoStringMethod(primitive(design.Integer))).Should(Equal(primitiveStringMethodCode("strconv.Itoa(int(ut))")))
+			Ω(codegen.GoStringMethod(primitive(design.Number))).Should(Equal(primitiveStringMethodCode("strconv.FormatFloat(float64(ut), 'g', -1, 64)")))
+			Ω(codegen.GoStringMethod(primitive(design.Duration))).Should(Equal(primitiveStringMethodCode("goa.Duration(ut).String()")))
+		})
+
+		It("panics with any values", func() {
+			Ω(func() { codegen.GoStringMethod(primitive(design.Any)) }).Should(Panic())
+		})
+	})
+})
+
+func primitiveStringMethodCode(expr string) string {
+	return `// String returns the underlying value of the Status instance.
+func (ut Status) String() string {
+	return ` + expr + `
+}
+`
+}
+
+const stringMethodCode = `// String returns a string representation of the Bottle instance.
+func (ut *Bottle) String() string {
+	if ut == nil {
+		return "<nil>"
+	}
+	fields := make([]string, 0, 2)
+	fields = append(fields, fmt.Sprintf("Name: %v", ut.Name))
+	if ut.Rating == nil {
+		fields = append(fields, "Rating: <nil>")
+	} else {
+		fields = append(fields, fmt.Sprintf("Rating: %v", *ut.Rating))
+	}
+	return "Bottle{" + strings.Join(fields, ", ") + "}"
+}
+`
+
+const displayStringMethodCode = `// String returns a string representation of the Bottle instance.
+func (ut *Bottle) String() string {
+	if ut == nil {
+		return "<nil>"
+	}
+	if ut.Rating == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", *ut.Rating)
+}
+`
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"text/template"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
+	"golang.org/x/text/unicode/norm"
 )
 
 // TransformMapKey is the name of the metadata used to specify the key for mapping fields when
@@ -56,6 +58,27 @@ func init() {
 	}
 }
 
+// StructMode defines how the fields of the generated structs are represented.
+type StructMode int
+
+const (
+	// PointerFields represents optional primitive fields with pointers, this is the default.
+	PointerFields StructMode = iota
+	// OptionalFields represents optional primitive fields with the generic Optional type.
+	// The generated code must include the Optional type definition returned by
+	// GoOptionalTypeDef. The fields that are not set are marshaled as JSON null.
+	OptionalFields
+	// BitmapFields represents optional primitive fields with values and records which fields
+	// are set in a bitmap held by an unexported "fieldsSet" field. The accessor methods that
+	// maintain the bitmap are returned by GoBitmapAccessors.
+	BitmapFields
+	// ValueFields represents optional primitive fields and objects with values rather than
+	// pointers, e.g. to produce the structs of gRPC messages from the same design as the
+	// pointer based HTTP structs. The unset fields hold the zero value. Recursive user types
+	// are still referred to with pointers so that the structs have a finite size.
+	ValueFields
+)
+
 // GoTypeDef returns the Go code that defines a Go type which matches the data structure
 // definition (the part that comes after `type foo`).
 // tabs is the number of tab character(s) used to tabulate the definition however the first
@@ -64,82 +87,272 @@ func init() {
 // private controls whether the field is a pointer or not. All fields in the struct are
 //   pointers for a private struct.
 func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string {
+	return GoTypeDefMode(ds, tabs, jsonTags, private, PointerFields)
+}
+
+// GoTypeDefMode is GoTypeDef where mode controls how the fields of the generated structs are
+// represented.
+func GoTypeDefMode(ds design.DataStructure, tabs int, jsonTags, private bool, mode StructMode) string {
+	return goTypeDef(ds, tabs, jsonTags, false, private, mode, nil)
+}
+
+// GoTypeDefFieldComments is GoTypeDefMode where each struct field is followed by a comment that
+// gives the JSON name of the field and whether it is required or optional, e.g.
+// `// json:"name" (required)`. The comments of the fields of a struct are aligned in a column to
+// ease the review of large generated structs.
+func GoTypeDefFieldComments(ds design.DataStructure, tabs int, jsonTags, private bool, mode StructMode) string {
+	return goTypeDef(ds, tabs, jsonTags, true, private, mode, nil)
+}
+
+// goTypeDef implements GoTypeDefMode, comments controls whether the struct fields are followed by
+// a comment (see GoTypeDefFieldComments) and imports is used to qualify the names of user types
+// defined in other packages if not nil.
+func goTypeDef(ds design.DataStructure, tabs int, jsonTags, comments, private bool, mode StructMode, imports *ImportSet) string {
 	def := ds.Definition()
 	t := def.Type
 	switch actual := t.(type) {
 	case design.Primitive:
-		return GoTypeName(t, nil, tabs, private)
+		if name := FlexibleTypeName(def); name != "" {
+			return name
+		}
+		return goTypeName(t, nil, tabs, private, mode, imports)
 	case *design.Array:
-		d := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
-		if actual.ElemType.Type.IsObject() {
+		d := goTypeDef(actual.ElemType, tabs, jsonTags, comments, private, mode, imports)
+		if isPointerObject(actual.ElemType.Type, mode) {
 			d = "*" + d
 		}
 		return "[]" + d
 	case *design.Hash:
-		keyDef := GoTypeDef(actual.KeyType, tabs, jsonTags, private)
-		if actual.KeyType.Type.IsObject() {
+		checkHashKey(actual)
+		keyDef := goTypeDef(actual.KeyType, tabs, jsonTags, comments, private, mode, imports)
+		if isPointerObject(actual.KeyType.Type, mode) {
 			keyDef = "*" + keyDef
 		}
-		elemDef := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
-		if actual.ElemType.Type.IsObject() {
+		elemDef := goTypeDef(actual.ElemType, tabs, jsonTags, comments, private, mode, imports)
+		if isPointerObject(actual.ElemType.Type, mode) {
 			elemDef = "*" + elemDef
 		}
 		return fmt.Sprintf("map[%s]%s", keyDef, elemDef)
 	case design.Object:
-		return goTypeDefObject(actual, def, tabs, jsonTags, private)
+		return goTypeDefObject(actual, def, tabs, jsonTags, comments, private, mode, imports)
 	case *design.UserTypeDefinition:
-		return GoTypeName(actual, actual.AllRequired(), tabs, private)
+		return goTypeName(actual, actual.AllRequired(), tabs, private, mode, imports)
 	case *design.MediaTypeDefinition:
-		return GoTypeName(actual, actual.AllRequired(), tabs, private)
+		return goTypeName(actual, actual.AllRequired(), tabs, private, mode, imports)
 	default:
 		panic("goa bug: unknown data structure type")
 	}
 }
 
-// goTypeDefObject returns the Go code that defines a Go struct.
-func goTypeDefObject(actual design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private bool) string {
+// goTypeDefObject returns the Go code that defines a Go struct. The struct embeds the user types
+// resolved from the "struct:embed" metadata of def and omits the attributes that they define.
+func goTypeDefObject(actual design.Object, def *design.AttributeDefinition, tabs int, jsonTags, comments, private bool, mode StructMode, imports *ImportSet) string {
 	var buffer bytes.Buffer
 	buffer.WriteString("struct {\n")
-	keys := make([]string, len(actual))
-	i := 0
+	promoted := make(map[string]bool)
+	for _, ut := range def.Embedded {
+		WriteTabs(&buffer, tabs+1)
+		buffer.WriteString(goTypeName(ut, nil, tabs+1, private, mode, imports) + "\n")
+		for n := range ut.Type.ToObject() {
+			promoted[n] = true
+		}
+	}
+	keys := make([]string, 0, len(actual))
 	for n := range actual {
-		keys[i] = n
-		i++
+		if !promoted[n] {
+			keys = append(keys, n)
+		}
 	}
 	sort.Strings(keys)
-	for _, name := range keys {
-		WriteTabs(&buffer, tabs+1)
+	fields := make([]string, len(keys))
+	for i, name := range keys {
 		field := actual[name]
-		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
-			typedef = "*" + typedef
-		}
-		fname := name
-		if field.Metadata != nil {
-			if tname, ok := field.Metadata["struct:field:name"]; ok {
-				if len(tname) > 0 {
-					fname = tname[0]
-				}
-			}
-		}
-		fname = Goify(fname, true)
+		typedef := fieldTypeRef(def, name, goTypeDef(field, tabs+1, jsonTags, comments, private, mode, imports), private, mode)
+		fname := goFieldName(name, field)
 		var tags string
 		if jsonTags {
-			tags = attributeTags(def, field, name, private)
+			tags = attributeTags(def, field, name, private, mode)
 		}
-		desc := actual[name].Description
-		if desc != "" {
-			desc = fmt.Sprintf("// %s\n\t", desc)
-		}
-		buffer.WriteString(fmt.Sprintf("%s%s %s%s\n", desc, fname, typedef, tags))
+		fields[i] = fmt.Sprintf("%s%s %s%s", fieldDoc(field), fname, typedef, tags)
+	}
+	if comments {
+		alignFieldComments(def, keys, fields)
+	}
+	for _, f := range fields {
+		WriteTabs(&buffer, tabs+1)
+		buffer.WriteString(f + "\n")
+	}
+	if mode == BitmapFields && len(bitmapFields(def, private)) > 0 {
+		WriteTabs(&buffer, tabs+1)
+		buffer.WriteString("fieldsSet uint64\n")
 	}
 	WriteTabs(&buffer, tabs)
 	buffer.WriteString("}")
 	return buffer.String()
 }
 
+// deprecatedKey is the name of the metadata that marks a field as deprecated, its value is the
+// reason given in the generated comment.
+const deprecatedKey = "struct:field:deprecated"
+
+// fieldDoc returns the comment lines written above the struct field generated for att: its
+// description followed by the "Deprecated:" paragraph recognized by the Go tools if the attribute
+// defines the "struct:field:deprecated" metadata.
+func fieldDoc(att *design.AttributeDefinition) string {
+	var doc string
+	if att.Description != "" {
+		doc = fmt.Sprintf("// %s\n\t", att.Description)
+	}
+	if reason, ok := att.Metadata[deprecatedKey]; ok {
+		if doc != "" {
+			doc += "//\n\t"
+		}
+		notice := "this field should not be used."
+		if len(reason) > 0 && reason[0] != "" {
+			notice = reason[0]
+		}
+		doc += fmt.Sprintf("// Deprecated: %s\n\t", notice)
+	}
+	return doc
+}
+
+// alignFieldComments appends the comments produced by GoTypeDefFieldComments to the code of the
+// fields of the struct generated for def, keys lists the names of the attributes of the fields.
+// The comments are aligned one space after the longest last line of the field definitions like
+// text/tabwriter would, ignoring the indentation.
+func alignFieldComments(def *design.AttributeDefinition, keys, fields []string) {
+	width := func(f string) int {
+		return utf8.RuneCountInString(strings.TrimLeft(f[strings.LastIndex(f, "\n")+1:], "\t"))
+	}
+	max := 0
+	for _, f := range fields {
+		if w := width(f); w > max {
+			max = w
+		}
+	}
+	for i, f := range fields {
+		status := "optional"
+		if def.IsRequired(keys[i]) {
+			status = "required"
+		}
+		fields[i] = fmt.Sprintf("%s%s // json:%q (%s)", f, strings.Repeat(" ", max-width(f)),
+			jsonFieldName(keys[i], def.Type.ToObject()[keys[i]]), status)
+	}
+}
+
+// jsonFieldName returns the JSON name of the field generated for the attribute att with the given
+// name, that is the name given by the "struct:tag:json" metadata if any, the attribute name
+// otherwise.
+func jsonFieldName(name string, att *design.AttributeDefinition) string {
+	if tag, ok := att.Metadata["struct:tag:json"]; ok && len(tag) > 0 {
+		if n := strings.Split(tag[0], ",")[0]; n != "" {
+			return n
+		}
+	}
+	return name
+}
+
+// goFieldName returns the name of the struct field generated for the attribute with the given
+// name, the "struct:field:name" metadata overrides the default.
+func goFieldName(name string, field *design.AttributeDefinition) string {
+	if field.Metadata != nil {
+		if tname, ok := field.Metadata["struct:field:name"]; ok {
+			if len(tname) > 0 {
+				name = tname[0]
+			}
+		}
+	}
+	return Goify(name, true)
+}
+
+// GoFieldTypeRef returns the Go code that refers to the type of the field generated for the
+// attribute with the given name. parent must be an object. mode controls how optional fields are
+// represented, see GoTypeDefMode.
+func GoFieldTypeRef(parent *design.AttributeDefinition, name string, tabs int, private bool, mode StructMode) string {
+	field := parent.Type.ToObject()[name]
+	return fieldTypeRef(parent, name, GoTypeName(field.Type, field.AllRequired(), tabs, private), private, mode)
+}
+
+// fieldTypeRef wraps the given field type definition with a pointer or an Optional according to
+// the field requiredness and to mode.
+func fieldTypeRef(parent *design.AttributeDefinition, name, typedef string, private bool, mode StructMode) string {
+	field := parent.Type.ToObject()[name]
+	if field.Type.IsObject() {
+		// objects are pointers even if required so that recursive types (A->A or A->B->A)
+		// have a finite size, ValueFields only uses pointers for recursive types
+		if isPointerObject(field.Type, mode) {
+			return "*" + typedef
+		}
+		return typedef
+	}
+	if isOptionalPrimitive(parent, name, private) {
+		switch mode {
+		case OptionalFields:
+			return "Optional[" + typedef + "]"
+		case BitmapFields, ValueFields:
+			return typedef
+		}
+		return "*" + typedef
+	}
+	return typedef
+}
+
+// isOptionalPrimitive returns true if the field generated for the attribute with the given name
+// may not be set and thus cannot be represented with a plain value.
+func isOptionalPrimitive(parent *design.AttributeDefinition, name string, private bool) bool {
+	field := parent.Type.ToObject()[name]
+	if isNilablePrimitive(field.Type) {
+		return false
+	}
+	return (field.Type.IsPrimitive() && private) || parent.IsPrimitivePointer(name)
+}
+
+// isNilablePrimitive returns true if the Go type generated for the primitive dt is nilable so that
+// nil stands for unset: *big.Int for BigInt and json.RawMessage for RawJSON.
+func isNilablePrimitive(dt design.DataType) bool {
+	return dt.Kind() == design.BigIntKind || dt.Kind() == design.RawJSONKind
+}
+
+// bitmapFields returns the names of the attributes of parent whose presence is recorded in the
+// bitmap of structs generated with BitmapFields, the index of a name is its bit.
+func bitmapFields(parent *design.AttributeDefinition, private bool) []string {
+	var names []string
+	parent.Type.ToObject().IterateAttributes(func(n string, _ *design.AttributeDefinition) error {
+		if isOptionalPrimitive(parent, n, private) {
+			names = append(names, n)
+		}
+		return nil
+	})
+	if len(names) > 64 {
+		panic(fmt.Sprintf("too many optional fields (%d), the bitmap representation supports up to 64", len(names)))
+	}
+	return names
+}
+
+// omitEmptyKey is the name of the metadata that disables the omitempty tag option of a field.
+const omitEmptyKey = "struct:field:omitempty"
+
+// ShouldOmitEmpty returns true if the JSON and XML tags of the field generated for the attribute
+// of parent with the given name may use the omitempty option, that is if the empty value of the
+// field stands for a missing value and not for a value that must be serialized. parent must be
+// an object and private and mode are the arguments given to GoTypeDefMode. omitempty is not used
+// for required fields, fields with a default value, optional primitive fields generated as plain
+// values in BitmapFields or ValueFields mode whose zero value may be set explicitly, optional
+// primitive fields generated as Optional structs in OptionalFields mode on which omitempty has no
+// effect and fields whose "struct:field:omitempty" metadata is "false".
+func ShouldOmitEmpty(parent *design.AttributeDefinition, name string, private bool, mode StructMode) bool {
+	att := parent.Type.ToObject()[name]
+	if val, ok := att.Metadata[omitEmptyKey]; ok && len(val) > 0 && val[0] == "false" {
+		return false
+	}
+	if !private && (parent.IsRequired(name) || parent.HasDefaultValue(name)) {
+		return false
+	}
+	return !((mode == BitmapFields || mode == ValueFields || mode == OptionalFields) && isOptionalPrimitive(parent, name, private))
+}
+
 // attributeTags computes the struct field tags.
-func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
+func attributeTags(parent, att *design.AttributeDefinition, name string, private bool, mode StructMode) string {
 	var elems []string
 	keys := make([]string, len(att.Metadata))
 	i := 0
@@ -150,7 +363,7 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 	sort.Strings(keys)
 	for _, key := range keys {
 		val := att.Metadata[key]
-		if strings.HasPrefix(key, "struct:tag:") {
+		if strings.HasPrefix(key, "struct:tag:") && key != jsonAliasKey {
 			name := key[11:]
 			value := strings.Join(val, ",")
 			elems = append(elems, fmt.Sprintf("%s:\"%s\"", name, value))
@@ -161,7 +374,7 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 	}
 	// Default algorithm
 	var omit string
-	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name)) {
+	if ShouldOmitEmpty(parent, name, private, mode) {
 		omit = ",omitempty"
 	}
 	return fmt.Sprintf(" `json:\"%s%s\" xml:\"%s%s\"`", name, omit, name, omit)
@@ -175,13 +388,50 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 // tabs is used to properly tabulate the object struct fields and only applies to this case.
 // This function assumes the type is in the same package as the code accessing it.
 func GoTypeRef(t design.DataType, required []string, tabs int, private bool) string {
-	tname := GoTypeName(t, required, tabs, private)
-	if t.IsObject() {
+	return goTypeRef(t, required, tabs, private, PointerFields, nil)
+}
+
+// GoTypeRefMode is GoTypeRef where mode controls how objects and the fields of inline objects
+// are represented, see StructMode.
+func GoTypeRefMode(t design.DataType, required []string, tabs int, private bool, mode StructMode) string {
+	return goTypeRef(t, required, tabs, private, mode, nil)
+}
+
+// goTypeRef implements GoTypeRefMode, see goTypeName.
+func goTypeRef(t design.DataType, required []string, tabs int, private bool, mode StructMode, imports *ImportSet) string {
+	tname := goTypeName(t, required, tabs, private, mode, imports)
+	if isPointerObject(t, mode) {
 		return "*" + tname
 	}
 	return tname
 }
 
+// goValueTypeRef returns the Go type of the values of att as declared by the struct fields that
+// GoTypeDef generates with JSON tags. Unlike the type returned by GoTypeRef the structs of inline
+// objects include the field tags so that the values can be assigned to the fields.
+func goValueTypeRef(att *design.AttributeDefinition, tabs int) string {
+	return goValueTypeRefMode(att, tabs, PointerFields)
+}
+
+// goValueTypeRefMode is goValueTypeRef where mode controls how objects and the fields of inline
+// objects are represented, see StructMode.
+func goValueTypeRefMode(att *design.AttributeDefinition, tabs int, mode StructMode) string {
+	d := GoTypeDefMode(att, tabs, true, false, mode)
+	if isPointerObject(att.Type, mode) {
+		return "*" + d
+	}
+	return d
+}
+
+// isPointerObject returns true if values of type t are referred to with pointers in the given
+// mode, that is if t is an object and mode is not ValueFields or t is recursive.
+func isPointerObject(t design.DataType, mode StructMode) bool {
+	if !t.IsObject() {
+		return false
+	}
+	return mode != ValueFields || isRecursive(t)
+}
+
 // GoTypeName returns the Go type name for a data type.
 // tabs is used to properly tabulate the object struct fields and only applies to this case.
 // This function assumes the type is in the same package as the code accessing it.
@@ -189,31 +439,38 @@ func GoTypeRef(t design.DataType, required []string, tabs int, private bool) str
 // case the type (Object) does not carry the required field information defined in the parent
 // (anonymous) attribute.
 func GoTypeName(t design.DataType, required []string, tabs int, private bool) string {
+	return goTypeName(t, required, tabs, private, PointerFields, nil)
+}
+
+// goTypeName implements GoTypeName, mode controls how inline objects are represented and imports
+// is used to qualify the names of user types defined in other packages if not nil.
+func goTypeName(t design.DataType, required []string, tabs int, private bool, mode StructMode, imports *ImportSet) string {
 	switch actual := t.(type) {
 	case design.Primitive:
 		return GoNativeType(t)
 	case *design.Array:
-		return "[]" + GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private)
+		return "[]" + goTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs, private, mode, imports)
 	case design.Object:
 		att := &design.AttributeDefinition{Type: actual}
 		if len(required) > 0 {
 			requiredVal := &dslengine.ValidationDefinition{Required: required}
 			att.Validation.Merge(requiredVal)
 		}
-		return GoTypeDef(att, tabs, false, private)
+		return goTypeDef(att, tabs, false, false, private, mode, imports)
 	case *design.Hash:
+		checkHashKey(actual)
 		return fmt.Sprintf(
 			"map[%s]%s",
-			GoTypeRef(actual.KeyType.Type, actual.KeyType.AllRequired(), tabs+1, private),
-			GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private),
+			goTypeRef(actual.KeyType.Type, actual.KeyType.AllRequired(), tabs, private, mode, imports),
+			goTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs, private, mode, imports),
 		)
 	case *design.UserTypeDefinition:
-		return Goify(actual.TypeName, !private)
+		return imports.qualify(actual, Goify(actual.TypeName, !private))
 	case *design.MediaTypeDefinition:
 		if builtin := BuiltInTypeName(actual); builtin != "" {
 			return builtin
 		}
-		return Goify(actual.TypeName, !private)
+		return imports.qualify(actual.UserTypeDefinition, Goify(actual.TypeName, !private))
 	default:
 		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
 	}
@@ -223,29 +480,16 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 func GoNativeType(t design.DataType) string {
 	switch actual := t.(type) {
 	case design.Primitive:
-		switch actual.Kind() {
-		case design.BooleanKind:
-			return "bool"
-		case design.IntegerKind:
-			return "int"
-		case design.NumberKind:
-			return "float64"
-		case design.StringKind:
-			return "string"
-		case design.DateTimeKind:
-			return "time.Time"
-		case design.UUIDKind:
-			return "uuid.UUID"
-		case design.AnyKind:
-			return "interface{}"
-		default:
-			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
+		if native, ok := primitiveGoTypes[actual.Kind()]; ok {
+			return native
 		}
+		panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 	case *design.Array:
 		return "[]" + GoNativeType(actual.ElemType.Type)
 	case design.Object:
 		return "map[string]interface{}"
 	case *design.Hash:
+		checkHashKey(actual)
 		return fmt.Sprintf("map[%s]%s", GoNativeType(actual.KeyType.Type), GoNativeType(actual.ElemType.Type))
 	case *design.MediaTypeDefinition:
 		return GoNativeType(actual.Type)
@@ -256,6 +500,147 @@ func GoNativeType(t design.DataType) string {
 	}
 }
 
+// primitiveGoTypes maps the primitive kinds to the Go types generated for them.
+var primitiveGoTypes = map[design.Kind]string{
+	design.BooleanKind:  "bool",
+	design.IntegerKind:  "int",
+	design.NumberKind:   "float64",
+	design.StringKind:   "string",
+	design.DateTimeKind: "time.Time",
+	design.UUIDKind:     "uuid.UUID",
+	design.AnyKind:      "interface{}",
+	design.BigIntKind:   "*big.Int",
+	design.DurationKind: "goa.Duration",
+	design.RawJSONKind:  "json.RawMessage",
+}
+
+// KindFromGoType returns the primitive kind whose values GoNativeType represents with the given
+// Go type, e.g. "int" produces IntegerKind. It returns false if goType is not the Go type of a
+// primitive.
+func KindFromGoType(goType string) (design.Kind, bool) {
+	for kind, native := range primitiveGoTypes {
+		if native == goType {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// checkHashKey asserts that the hash key type is not Any, design validation rejects such hashes.
+func checkHashKey(h *design.Hash) {
+	if h.KeyType.Type.Kind() == design.AnyKind {
+		panic("goa bug: Any hash key type") // bug
+	}
+}
+
+// IsComparable returns true if the values of the Go type generated for dt can be compared with
+// ==. Primitives are comparable except for Any, whose values may hold non comparable types, BigInt,
+// whose values are pointers that would be compared instead of the integers, and RawJSON, whose
+// values are slices. Arrays and hashes are not comparable. Objects are comparable if all their
+// fields are.
+func IsComparable(dt design.DataType) bool {
+	return isComparable(dt, make(map[string]bool))
+}
+
+// isComparable implements IsComparable, seen records the user types being visited to break
+// cycles.
+func isComparable(dt design.DataType, seen map[string]bool) bool {
+	switch actual := dt.(type) {
+	case design.Primitive:
+		return actual.Kind() != design.AnyKind && !isNilablePrimitive(actual)
+	case *design.Array, *design.Hash:
+		return false
+	case design.Object:
+		for _, att := range actual {
+			if !isComparable(att.Type, seen) {
+				return false
+			}
+		}
+		return true
+	case *design.UserTypeDefinition:
+		if seen[actual.TypeName] {
+			return true
+		}
+		seen[actual.TypeName] = true
+		return isComparable(actual.Type, seen)
+	case *design.MediaTypeDefinition:
+		if seen[actual.TypeName] {
+			return true
+		}
+		seen[actual.TypeName] = true
+		return isComparable(actual.Type, seen)
+	default:
+		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
+	}
+}
+
+// isRecursive returns true if t is a user type whose definition refers to itself directly or
+// through other user types.
+func isRecursive(t design.DataType) bool {
+	if !isUserType(t) {
+		return false
+	}
+	return refersTo(underlyingAttribute(&design.AttributeDefinition{Type: t}).Type, userTypeName(t), make(map[string]bool))
+}
+
+// refersTo returns true if dt is or refers to the user type with the given name, seen records the
+// user types being visited to break cycles.
+func refersTo(dt design.DataType, name string, seen map[string]bool) bool {
+	switch actual := dt.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		n := userTypeName(actual)
+		if n == name {
+			return true
+		}
+		if seen[n] {
+			return false
+		}
+		seen[n] = true
+		return refersTo(underlyingAttribute(&design.AttributeDefinition{Type: dt}).Type, name, seen)
+	case *design.Array:
+		return refersTo(actual.ElemType.Type, name, seen)
+	case *design.Hash:
+		return refersTo(actual.KeyType.Type, name, seen) || refersTo(actual.ElemType.Type, name, seen)
+	case design.Object:
+		for _, att := range actual {
+			if refersTo(att.Type, name, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GoMethodSignature returns the Go signature of the method with the given name that accepts the
+// given payload and returns the given result, e.g.
+// `Show(ctx context.Context, p *ShowPayload) (*Bottle, error)`. payload and result may be nil in
+// which case the signature omits the corresponding parameter and return value.
+func GoMethodSignature(name string, payload, result design.DataType) string {
+	params := "ctx context.Context"
+	if !isNilType(payload) {
+		params += ", p " + GoTypeRef(payload, nil, 0, false)
+	}
+	results := "error"
+	if !isNilType(result) {
+		results = "(" + GoTypeRef(result, nil, 0, false) + ", error)"
+	}
+	return fmt.Sprintf("%s(%s) %s", GoifyMethod(name), params, results)
+}
+
+// isNilType returns true if t is nil or is a nil user type or media type such as the payload of an
+// action that does not define one.
+func isNilType(t design.DataType) bool {
+	switch actual := t.(type) {
+	case nil:
+		return true
+	case *design.UserTypeDefinition:
+		return actual == nil
+	case *design.MediaTypeDefinition:
+		return actual == nil
+	}
+	return false
+}
+
 // GoTypeDesc returns the description of a type.  If no description is defined
 // for the type, one will be generated.
 func GoTypeDesc(t design.DataType, upper bool) string {
@@ -332,26 +717,274 @@ var commonInitialisms = map[string]bool{
 	"XSS":   true,
 }
 
+// CommonInitialisms returns the initialisms that Goify writes in uppercase, e.g. "ID", in
+// alphabetical order.
+func CommonInitialisms() []string {
+	res := make([]string, 0, len(commonInitialisms))
+	for i := range commonInitialisms {
+		res = append(res, i)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// GoifyOptions controls the behavior of GoifyWith. The zero value produces an unexported
+// identifier.
+type GoifyOptions struct {
+	// FirstUpper makes the first character of the identifier uppercase, lowercase otherwise.
+	FirstUpper bool
+	// SplitInitialisms recognizes common initialisms at the beginning of words that are all
+	// lowercase, e.g. "apikey" produces "APIKey" instead of "Apikey". Splitting words that
+	// have no separator is inherently ambiguous so that only initialisms of at least 3
+	// characters are considered and the shortest match wins ("httpserver" produces
+	// "HTTPServer").
+	SplitInitialisms bool
+	// SplitDigits makes a digit followed by a letter end a word, e.g. "http2server" produces
+	// "HTTP2Server" instead of "HTTP2server".
+	SplitDigits bool
+	// ExactInitialisms restricts initialisms to words that match one exactly, words are only
+	// delimited by separators and case changes and the other words are title cased, e.g.
+	// "api_key" produces "APIKey" but "apikey" produces "Apikey". ExactInitialisms takes
+	// precedence over the options that split words further (SplitInitialisms and SplitDigits)
+	// so that the output does not change as more such options are introduced.
+	ExactInitialisms bool
+	// TitleCaseInitialisms writes the initialisms in title case rather than in uppercase as
+	// some style guides prefer, e.g. "http_server" produces "HttpServer" instead of
+	// "HTTPServer". The initialisms that start unexported identifiers are lowercase either way,
+	// e.g. "httpServer".
+	TitleCaseInitialisms bool
+	// VersionJoiner is written between consecutive words made of digits so that version like
+	// names stay readable, e.g. "v2_0_1" produces "V2_0_1" with "_" instead of "V201". It must
+	// only contain characters valid in identifiers for the result to be one.
+	VersionJoiner string
+	// StopWords lists the lowercase words removed from the identifiers, e.g. with "the" and "of"
+	// "the_user_id" produces "UserID". Words that are initialisms are never removed and neither
+	// are the words of strings made only of stop words so that the identifier is not empty.
+	StopWords map[string]bool
+	// ReservedWords controls how the identifiers that are Go reserved words are handled, see
+	// ReservedWordPolicy.
+	ReservedWords ReservedWordPolicy
+	// Overrides maps strings to the identifiers produced for them, e.g.
+	// {"id_legacy": "LegacyID"}. The identifiers are used verbatim and the other strings follow
+	// the rules defined by the other options.
+	Overrides map[string]string
+}
+
+// ReservedWordPolicy defines how GoifyWith handles the identifiers that are Go reserved words.
+type ReservedWordPolicy int
+
+const (
+	// SuffixReserved appends "_" to the identifiers that are reserved words once cased. As the
+	// reserved words are lowercase this only affects unexported identifiers: "type" produces
+	// "type_" unexported but "Type" exported.
+	SuffixReserved ReservedWordPolicy = iota
+	// CapitalizeReserved makes the first letter of the unexported identifiers that are reserved
+	// words uppercase instead, "type" produces "Type" under both casings.
+	CapitalizeReserved
+	// AlwaysSuffixReserved appends "_" to the identifiers whose lowercase form is a reserved
+	// word regardless of their casing, "type" produces "type_" and "Type_".
+	AlwaysSuffixReserved
+)
+
 // Goify makes a valid Go identifier out of any string.
 // It does that by removing any non letter and non digit character and by making sure the first
-// character is a letter or "_".
+// character is a letter or "_". Apostrophes are removed without separating words so that
+// "user's_profile" produces "UsersProfile" and "O'Brien" produces "OBrien". Non empty strings
+// without any letter or digit such as "_" or "-" produce "_".
 // Goify produces a "CamelCase" version of the string, if firstUpper is true the first character
-// of the identifier is uppercase otherwise it's lowercase.
+// of the identifier is uppercase otherwise it's lowercase. Unless it is an initialism the case of
+// the other characters of the first word is preserved, this includes first words made of a single
+// letter: "eCommerce" produces "eCommerce" or "ECommerce" and "XUser" produces "xUser" or "XUser".
+// Reserved words are checked against the final casing and suffixed with "_" so that "type"
+// produces "type_" unexported but "Type" exported, see ReservedWordPolicy for alternatives.
 func Goify(str string, firstUpper bool) string {
-	runes := []rune(str)
+	return GoifyWith(str, GoifyOptions{FirstUpper: firstUpper})
+}
+
+// GoifyInitialisms is Goify with the SplitInitialisms option set.
+func GoifyInitialisms(str string, firstUpper bool) string {
+	return GoifyWith(str, GoifyOptions{FirstUpper: firstUpper, SplitInitialisms: true})
+}
+
+// GoifyMethod makes a valid exported Go method name out of any string. Unlike Goify(str, true)
+// whose result starts with the first valid character of str the result is always exported:
+// names that do not start with an uppercase letter once Goified, e.g. "2fa" or names written in
+// a script without case, are prefixed with "X". Names that are reserved words are suffixed with
+// "_" as with Goify. The names of the methods of generated code must be produced with GoifyMethod so that
+// collisions with the generated fields can be detected with CheckMethodCollisions.
+func GoifyMethod(str string) string {
+	name := Goify(str, true)
+	if len(SplitWords(str)) == 0 {
+		// do not keep the "_" fallback of Goify, "X" is enough
+		name = ""
+	}
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(r) {
+		name = "X" + name
+	}
+	return fixReserved(name)
+}
+
+// GoifyStrict is Goify where the strings that cannot produce a meaningful identifier cause an
+// error instead of the fallbacks Goify uses: strings without any letter or digit which produce an
+// empty identifier or "_", strings whose first valid character is a digit which produce an invalid
+// identifier and strings that produce a Go reserved word which Goify suffixes with "_". It makes
+// it possible to report bad attribute names to the authors of the design.
+func GoifyStrict(str string, firstUpper bool) (string, error) {
+	name := Goify(str, firstUpper)
+	if len(SplitWords(str)) == 0 {
+		return "", fmt.Errorf("%#v cannot be used as an identifier, it contains no letter or digit", str)
+	}
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(r) {
+		return "", fmt.Errorf("%#v cannot be used as an identifier, it starts with a digit", str)
+	}
+	if w := strings.TrimSuffix(name, "_"); w != name && reserved[w] {
+		return "", fmt.Errorf("%#v cannot be used as an identifier, it is the Go reserved word %#v", str, w)
+	}
+	return name, nil
+}
+
+// GoifyWith is Goify where opts controls how the identifier is produced, see GoifyOptions.
+// GoifyWith is idempotent: the identifier it produces is left unchanged by another call with the
+// same options unless it comes from Overrides.
+func GoifyWith(str string, opts GoifyOptions) string {
+	if id, ok := opts.Overrides[str]; ok {
+		return id
+	}
+	res := goify(str, opts)
+	// The words of the identifier may differ from the words of str, e.g. "uRi" produces "URi"
+	// whose first word is the "URI" initialism, so process it again until it is stable. Each
+	// pass only merges words into initialisms or splits them so that this terminates quickly.
+	for i := 0; i < len(res); i++ {
+		next := goify(res, opts)
+		if next == res {
+			break
+		}
+		res = next
+	}
+	if res == "" && str != "" {
+		return "_"
+	}
+	return res
+}
+
+// goify implements a single pass of GoifyWith.
+func goify(str string, opts GoifyOptions) string {
+	firstUpper := opts.FirstUpper
+	var buf bytes.Buffer
+	buf.Grow(len(str))
+	var prev string
+	for k, word := range removeStopWords(splitWords(str, opts), opts.StopWords) {
+		if k > 0 && opts.VersionJoiner != "" && lastIsDigit(prev) && firstIsDigit(word) {
+			buf.WriteString(opts.VersionJoiner)
+		}
+		prev = word
+		// is it one of our initialisms?
+		if u := strings.ToUpper(word); commonInitialisms[u] {
+			if !firstUpper && k == 0 {
+				u = strings.ToLower(u)
+			} else if opts.TitleCaseInitialisms {
+				u = u[:1] + strings.ToLower(u[1:])
+			}
+			// All the common initialisms are ASCII,
+			// so we can replace the bytes exactly.
+			buf.WriteString(u)
+			continue
+		}
+		lower := strings.ToLower(word) == word
+		switch {
+		case k == 0 && !firstUpper:
+			writeFirst(&buf, word, unicode.ToLower)
+		case lower && (k > 0 || firstUpper):
+			// already all lowercase, uppercase the first character unless it is the first
+			// word of an unexported identifier.
+			writeFirst(&buf, word, unicode.ToUpper)
+		default:
+			buf.WriteString(word)
+		}
+	}
+
+	return fixReservedWith(buf.String(), opts.ReservedWords)
+}
+
+// removeStopWords returns the words that are not in stopWords or that are initialisms, it returns
+// words unchanged if they are all stop words.
+func removeStopWords(words []string, stopWords map[string]bool) []string {
+	if len(stopWords) == 0 {
+		return words
+	}
+	var kept []string
+	for _, w := range words {
+		if !stopWords[strings.ToLower(w)] || commonInitialisms[strings.ToUpper(w)] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		return words
+	}
+	return kept
+}
+
+// writeFirst writes word to buf with its first character mapped by f.
+func writeFirst(buf *bytes.Buffer, word string, f func(rune) rune) {
+	if c := word[0]; c < utf8.RuneSelf {
+		buf.WriteByte(byte(f(rune(c))))
+		buf.WriteString(word[1:])
+		return
+	}
+	r, size := utf8.DecodeRuneInString(word)
+	buf.WriteRune(f(r))
+	buf.WriteString(word[size:])
+}
+
+// firstIsDigit returns true if the first character of the non empty word is a digit.
+func firstIsDigit(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsDigit(r)
+}
+
+// lastIsDigit returns true if the last character of the non empty word is a digit.
+func lastIsDigit(word string) bool {
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return unicode.IsDigit(r)
+}
+
+// SplitWords returns the words that Goify detects in the given string and uses to produce
+// identifiers, e.g. "user_id" produces "user" and "id" and "HTTPServer" produces "HTTPServer".
+// Words are delimited by characters that are not valid in identifiers, which are removed, and by
+// lowercase to non lowercase transitions. Apostrophes are removed but do not delimit words. The case of the words is left unchanged so that other
+// naming conventions such as snake case may be built on top of it.
+func SplitWords(str string) []string {
+	return splitWords(str, GoifyOptions{})
+}
+
+// splitWords implements SplitWords, opts controls how words without separators are split, see
+// GoifyOptions.
+func splitWords(str string, opts GoifyOptions) []string {
+	if opts.ExactInitialisms {
+		opts.SplitInitialisms, opts.SplitDigits = false, false
+	}
+	if isASCII(str) {
+		return mergeDigitInitialisms(splitWordsASCII(str, opts))
+	}
+	// compose combining sequences so that letters with diacritics are single runes, runes is a
+	// fresh slice owned by splitWords so that invalid characters can be removed in place below
+	runes := []rune(strings.Map(removeNonBoundary, norm.NFC.String(str)))
+	var words []string
 	w, i := 0, 0 // index of start of word, scan
 	for i+1 <= len(runes) {
 		eow := false // whether we hit the end of a word
-		if i+1 == len(runes) {
-			eow = true
-		} else if !validIdentifier(runes[i]) {
+		if !validIdentifier(runes[i]) {
 			// get rid of it
 			runes = append(runes[:i], runes[i+1:]...)
-		} else if runes[i+1] == '_' {
-			// underscore; shift the remainder forward over any run of underscores
+			continue
+		} else if i+1 == len(runes) {
+			eow = true
+		} else if !validIdentifier(runes[i+1]) {
+			// underscore or other invalid character; shift the remainder forward over any
+			// run of invalid characters
 			eow = true
 			n := 1
-			for i+n+1 < len(runes) && runes[i+n+1] == '_' {
+			for i+n+1 < len(runes) && !validIdentifier(runes[i+n+1]) {
 				n++
 			}
 			copy(runes[i+1:], runes[i+n+1:])
@@ -359,6 +992,9 @@ func Goify(str string, firstUpper bool) string {
 		} else if unicode.IsLower(runes[i]) && !unicode.IsLower(runes[i+1]) {
 			// lower->non-lower
 			eow = true
+		} else if opts.SplitDigits && unicode.IsDigit(runes[i]) && unicode.IsLetter(runes[i+1]) {
+			// digit->letter
+			eow = true
 		}
 		i++
 		if !eow {
@@ -367,31 +1003,161 @@ func Goify(str string, firstUpper bool) string {
 
 		// [w,i] is a word.
 		word := string(runes[w:i])
-		// is it one of our initialisms?
-		if u := strings.ToUpper(word); commonInitialisms[u] {
-			if firstUpper {
-				u = strings.ToUpper(u)
-			} else if w == 0 {
-				u = strings.ToLower(u)
+		if opts.SplitInitialisms && strings.ToLower(word) == word && !commonInitialisms[strings.ToUpper(word)] {
+			if n := initialismPrefix(word); n > 0 {
+				// only consume the initialism, the remainder is the next word
+				i = w + n
+				word = string(runes[w:i])
 			}
-
-			// All the common initialisms are ASCII,
-			// so we can replace the bytes exactly.
-			copy(runes[w:], []rune(u))
-		} else if w > 0 && strings.ToLower(word) == word {
-			// already all lowercase, and not the first word, so uppercase the first character.
-			runes[w] = unicode.ToUpper(runes[w])
-		} else if w == 0 && strings.ToLower(word) == word && firstUpper {
-			runes[w] = unicode.ToUpper(runes[w])
-		}
-		if w == 0 && !firstUpper {
-			runes[w] = unicode.ToLower(runes[w])
 		}
+		words = append(words, word)
 		//advance to next word
 		w = i
 	}
+	return mergeDigitInitialisms(words)
+}
+
+// mergeDigitInitialisms merges the words made of digits into the preceding words when the result
+// is an initialism that ends with digits such as "UTF8", the lower->non-lower transition would
+// split "utf8" into "utf" and "8" otherwise. The merged words are returned in a new slice, words
+// is left unchanged.
+func mergeDigitInitialisms(words []string) []string {
+	if len(words) == 0 {
+		return words
+	}
+	merged := make([]string, 1, len(words))
+	merged[0] = words[0]
+	for _, w := range words[1:] {
+		last := len(merged) - 1
+		if isDigits(w) && commonInitialisms[strings.ToUpper(merged[last]+w)] {
+			merged[last] += w
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// isDigits returns true if word is only made of digits.
+func isDigits(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitWordsASCII is splitWords for strings made of ASCII characters only. Such strings need
+// neither normalization nor Unicode classification so that the words are sliced directly out of
+// the string, most names found in designs take this path. It must produce the same words as
+// splitWords.
+func splitWordsASCII(str string, opts GoifyOptions) []string {
+	if strings.IndexByte(str, '\'') >= 0 {
+		str = strings.Replace(str, "'", "", -1)
+	}
+	var words []string
+	for s := 0; s < len(str); {
+		if !validASCIIIdentifier(str[s]) {
+			s++
+			continue
+		}
+		// [s,e) is a run of valid characters, words are delimited by the lower->non-lower and
+		// digit->letter transitions
+		e := s + 1
+		for e < len(str) && validASCIIIdentifier(str[e]) {
+			e++
+		}
+		w := s // index of start of word
+		for i := s + 1; i <= e; i++ {
+			if i < e && !asciiWordBoundary(str[i-1], str[i], opts) {
+				continue
+			}
+			word := str[w:i]
+			if opts.SplitInitialisms && strings.ToLower(word) == word && !commonInitialisms[strings.ToUpper(word)] {
+				if n := initialismPrefix(word); n > 0 {
+					// only consume the initialism, the remainder is the next word
+					i = w + n
+					word = str[w:i]
+				}
+			}
+			words = append(words, word)
+			w = i
+		}
+		s = e
+	}
+	return words
+}
+
+// asciiWordBoundary returns true if a word ends between the ASCII characters a and b.
+func asciiWordBoundary(a, b byte, opts GoifyOptions) bool {
+	aLower := 'a' <= a && a <= 'z'
+	if aLower && !('a' <= b && b <= 'z') {
+		return true
+	}
+	return opts.SplitDigits && '0' <= a && a <= '9' && ('a' <= b && b <= 'z' || 'A' <= b && b <= 'Z')
+}
+
+// isASCII returns true if str only contains ASCII characters.
+func isASCII(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
 
-	return fixReserved(string(runes))
+// validASCIIIdentifier is validIdentifier for ASCII characters.
+func validASCIIIdentifier(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// initialismPrefix returns the length of the shortest common initialism of at least 3
+// characters that starts the given word and is shorter than the word, 0 if there isn't one.
+// The initialisms are ASCII so that the length is both a number of bytes and of runes.
+func initialismPrefix(word string) int {
+	for n := 3; n < len(word); n++ {
+		if commonInitialisms[strings.ToUpper(word[:n])] {
+			return n
+		}
+	}
+	return 0
+}
+
+// GoPackageName makes a valid Go package name out of any string.
+// Unlike Goify it does not produce a CamelCase identifier: the result is all lowercase and any
+// non letter and non digit character (including underscores) is removed. A "pkg" prefix is added
+// if the result starts with a digit and a "pkg" suffix is added if the result is a Go keyword or
+// the name of a package used by the generated code. GoPackageName returns "pkg" if str contains
+// no letter or digit, e.g. "_" or "-".
+func GoPackageName(str string) string {
+	var buf bytes.Buffer
+	for _, r := range str {
+		if validIdentifier(r) {
+			buf.WriteRune(unicode.ToLower(r))
+		}
+	}
+	name := buf.String()
+	if name == "" {
+		return "pkg"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "pkg" + name
+	}
+	if _, ok := reserved[name]; ok {
+		name += "pkg"
+	}
+	return name
+}
+
+// removeNonBoundary is the strings.Map function that drops the invalid characters that do not
+// delimit words such as the apostrophe in "user's".
+func removeNonBoundary(r rune) rune {
+	if r == '\'' || r == '\u2019' {
+		return -1
+	}
+	return r
 }
 
 // validIdentifier returns true if the rune is a letter or number
@@ -407,6 +1173,21 @@ func fixReserved(w string) string {
 	return w
 }
 
+// fixReservedWith applies the given policy to the identifier w, see ReservedWordPolicy.
+func fixReservedWith(w string, policy ReservedWordPolicy) string {
+	switch policy {
+	case CapitalizeReserved:
+		if reserved[w] {
+			return strings.ToUpper(w[:1]) + w[1:]
+		}
+	case AlwaysSuffixReserved:
+		if reserved[strings.ToLower(w)] {
+			return w + "_"
+		}
+	}
+	return fixReserved(w)
+}
+
 // GoTypeTransform produces Go code that initializes the data structure defined by target from an
 // instance of the data structure described by source. The algorithm matches object fields by name
 // or using the value of the "transform:key" attribute metadata when present.
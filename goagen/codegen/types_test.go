@@ -2,6 +2,7 @@ package codegen_test
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -126,6 +127,17 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("with decomposed accents", func() {
+				BeforeEach(func() {
+					firstUpper = true
+					str = "cafe\u0301_id"
+					expected = "Caf\u00e9ID"
+				})
+				It("keeps the accented letters", func() {
+					Ω(goified).Should(Equal(expected))
+				})
+			})
+
 			Context("with only UUID and firstupper false", func() {
 				BeforeEach(func() {
 					firstUpper = false
@@ -141,6 +153,762 @@ var _ = Describe("code generation", func() {
 
 	})
 
+	Describe("Goify with single letter first words", func() {
+		It("only changes the case of the first letter", func() {
+			cases := []struct{ str, lower, upper string }{
+				{"aUser", "aUser", "AUser"},
+				{"xRay", "xRay", "XRay"},
+				{"eCommerce", "eCommerce", "ECommerce"},
+				{"XUser", "xUser", "XUser"},
+				{"a_user", "aUser", "AUser"},
+			}
+			for _, c := range cases {
+				Ω(codegen.Goify(c.str, false)).Should(Equal(c.lower), c.str)
+				Ω(codegen.Goify(c.str, true)).Should(Equal(c.upper), c.str)
+			}
+		})
+	})
+
+	Describe("Goify with apostrophes", func() {
+		It("removes them without separating words", func() {
+			cases := []struct{ str, lower, upper string }{
+				{"user's_profile", "usersProfile", "UsersProfile"},
+				{"O'Brien", "oBrien", "OBrien"},
+				{"it’s", "its", "Its"},
+				{"rock 'n' roll", "rockNRoll", "RockNRoll"},
+			}
+			for _, c := range cases {
+				Ω(codegen.Goify(c.str, false)).Should(Equal(c.lower), c.str)
+				Ω(codegen.Goify(c.str, true)).Should(Equal(c.upper), c.str)
+			}
+		})
+
+		It("keeps other quotes as word boundaries", func() {
+			Ω(codegen.Goify(`say"hello"`, true)).Should(Equal("SayHello"))
+		})
+	})
+
+	Describe("Goify with header names", func() {
+		It("treats dashes as word boundaries", func() {
+			headers := map[string]string{
+				"Content-Type":     "ContentType",
+				"X-Request-ID":     "XRequestID",
+				"x-request-id":     "XRequestID",
+				"X-Forwarded-For":  "XForwardedFor",
+				"If-None-Match":    "IfNoneMatch",
+				"Content-MD5":      "ContentMD5",
+				"X-API-Key":        "XAPIKey",
+				"Accept--Encoding": "AcceptEncoding",
+				"x-b3-traceid":     "XB3Traceid",
+			}
+			for header, expected := range headers {
+				Ω(codegen.Goify(header, true)).Should(Equal(expected), header)
+			}
+		})
+
+		It("does not absorb single letter words", func() {
+			Ω(codegen.Goify("a-b", true)).Should(Equal("AB"))
+			Ω(codegen.Goify("x-y-z", false)).Should(Equal("xYZ"))
+		})
+	})
+
+	Describe("GoifyWith exact initialisms", func() {
+		It("only recognizes words matching an initialism", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, ExactInitialisms: true}
+			Ω(codegen.GoifyWith("api_key", opts)).Should(Equal("APIKey"))
+			Ω(codegen.GoifyWith("apikey", opts)).Should(Equal("Apikey"))
+			Ω(codegen.GoifyWith("user-id", opts)).Should(Equal("UserID"))
+			Ω(codegen.GoifyWith("http2server", opts)).Should(Equal("HTTP2server"))
+		})
+
+		It("takes precedence over the splitting options", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, SplitInitialisms: true, SplitDigits: true, ExactInitialisms: true}
+			Ω(codegen.GoifyWith("apikey", opts)).Should(Equal("Apikey"))
+			Ω(codegen.GoifyWith("http2server", opts)).Should(Equal("HTTP2server"))
+		})
+	})
+
+	Describe("Goify with numeric words", func() {
+		It("checks the words between separators against the initialisms", func() {
+			for _, o := range []codegen.GoifyOptions{
+				{FirstUpper: true},
+				{FirstUpper: true, SplitInitialisms: true},
+				{FirstUpper: true, SplitDigits: true},
+				{FirstUpper: true, SplitInitialisms: true, SplitDigits: true},
+			} {
+				Ω(codegen.GoifyWith("v1_api_2", o)).Should(Equal("V1API2"), fmt.Sprintf("%+v", o))
+				Ω(codegen.GoifyWith("v2_http_3_id", o)).Should(Equal("V2HTTP3ID"), fmt.Sprintf("%+v", o))
+			}
+			Ω(codegen.Goify("v1_api_2", false)).Should(Equal("v1API2"))
+			Ω(codegen.GoifyInitialisms("v1_apikey_2", true)).Should(Equal("V1APIKey2"))
+		})
+	})
+
+	Describe("Goify with trailing initialisms", func() {
+		It("recognizes the last camel case word regardless of its case", func() {
+			for in, out := range map[string]string{
+				"parseUrl": "ParseURL",
+				"getId":    "GetID",
+				"toJson":   "ToJSON",
+				"userHttp": "UserHTTP",
+				"parseURL": "ParseURL",
+			} {
+				Ω(codegen.Goify(in, true)).Should(Equal(out), in)
+			}
+			Ω(codegen.Goify("parseUrl", false)).Should(Equal("parseURL"))
+			Ω(codegen.Goify("getId", false)).Should(Equal("getID"))
+			Ω(codegen.GoifyInitialisms("toJson", true)).Should(Equal("ToJSON"))
+		})
+	})
+
+	Describe("GoifyWith title case initialisms", func() {
+		It("title cases the initialisms", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, TitleCaseInitialisms: true}
+			Ω(codegen.GoifyWith("http_server", opts)).Should(Equal("HttpServer"))
+			Ω(codegen.GoifyWith("user_id", opts)).Should(Equal("UserId"))
+			Ω(codegen.GoifyWith("utf8", opts)).Should(Equal("Utf8"))
+			opts.SplitInitialisms = true
+			Ω(codegen.GoifyWith("httpserver", opts)).Should(Equal("HttpServer"))
+		})
+
+		It("lowercases the first initialism of unexported identifiers", func() {
+			opts := codegen.GoifyOptions{TitleCaseInitialisms: true}
+			Ω(codegen.GoifyWith("http_server", opts)).Should(Equal("httpServer"))
+			Ω(codegen.GoifyWith("api_key_id", opts)).Should(Equal("apiKeyId"))
+		})
+
+		It("is idempotent", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, TitleCaseInitialisms: true}
+			for _, name := range []string{"http_server", "HttpServer", "xml_http_request", "utf8_uri"} {
+				once := codegen.GoifyWith(name, opts)
+				Ω(codegen.GoifyWith(once, opts)).Should(Equal(once), name)
+			}
+		})
+	})
+
+	Describe("GoifyWith reserved words", func() {
+		words := []string{"type", "range", "func"}
+
+		It("suffixes the unexported reserved words by default", func() {
+			for _, w := range words {
+				upper := strings.ToUpper(w[:1]) + w[1:]
+				Ω(codegen.Goify(w, false)).Should(Equal(w + "_"))
+				Ω(codegen.Goify(w, true)).Should(Equal(upper))
+				Ω(codegen.GoifyWith(w, codegen.GoifyOptions{ReservedWords: codegen.SuffixReserved})).Should(Equal(w + "_"))
+			}
+		})
+
+		It("capitalizes the reserved words", func() {
+			for _, w := range words {
+				upper := strings.ToUpper(w[:1]) + w[1:]
+				for _, first := range []bool{false, true} {
+					opts := codegen.GoifyOptions{FirstUpper: first, ReservedWords: codegen.CapitalizeReserved}
+					Ω(codegen.GoifyWith(w, opts)).Should(Equal(upper))
+				}
+			}
+		})
+
+		It("always suffixes the reserved words", func() {
+			for _, w := range words {
+				upper := strings.ToUpper(w[:1]) + w[1:]
+				opts := codegen.GoifyOptions{ReservedWords: codegen.AlwaysSuffixReserved}
+				Ω(codegen.GoifyWith(w, opts)).Should(Equal(w + "_"))
+				opts.FirstUpper = true
+				Ω(codegen.GoifyWith(w, opts)).Should(Equal(upper + "_"))
+				Ω(codegen.GoifyWith(upper+"_", opts)).Should(Equal(upper + "_"))
+			}
+		})
+
+		It("only checks whole identifiers", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, ReservedWords: codegen.AlwaysSuffixReserved}
+			Ω(codegen.GoifyWith("type_name", opts)).Should(Equal("TypeName"))
+		})
+	})
+
+	Describe("GoifyWith stop words", func() {
+		stop := map[string]bool{"the": true, "of": true, "a": true, "id": true}
+
+		It("removes the stop words", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, StopWords: stop}
+			Ω(codegen.GoifyWith("the_user_name", opts)).Should(Equal("UserName"))
+			Ω(codegen.GoifyWith("TheNameOfTheUser", opts)).Should(Equal("NameUser"))
+			Ω(codegen.GoifyWith("the_user_name", codegen.GoifyOptions{StopWords: stop})).Should(Equal("userName"))
+		})
+
+		It("keeps the initialisms", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, StopWords: stop}
+			Ω(codegen.GoifyWith("the_user_id", opts)).Should(Equal("UserID"))
+			opts.SplitInitialisms = true
+			Ω(codegen.GoifyWith("the_apikey", opts)).Should(Equal("APIKey"))
+		})
+
+		It("keeps the words of strings made of stop words", func() {
+			Ω(codegen.GoifyWith("the_a", codegen.GoifyOptions{FirstUpper: true, StopWords: stop})).Should(Equal("TheA"))
+		})
+
+		It("is opt-in", func() {
+			Ω(codegen.Goify("the_user_id", true)).Should(Equal("TheUserID"))
+		})
+	})
+
+	Describe("GoifyWith version joiner", func() {
+		It("separates consecutive numeric words", func() {
+			cases := []struct{ str, plain, joined string }{
+				{"v2_0_1", "V201", "V2_0_1"},
+				{"api_v3", "APIV3", "APIV3"},
+				{"2020_report", "2020Report", "2020Report"},
+			}
+			for _, c := range cases {
+				Ω(codegen.GoifyWith(c.str, codegen.GoifyOptions{FirstUpper: true})).Should(Equal(c.plain), c.str)
+				joined := codegen.GoifyWith(c.str, codegen.GoifyOptions{FirstUpper: true, VersionJoiner: "_"})
+				Ω(joined).Should(Equal(c.joined), c.str)
+				Ω(codegen.GoifyWith(joined, codegen.GoifyOptions{FirstUpper: true, VersionJoiner: "_"})).Should(Equal(joined), c.str)
+			}
+		})
+	})
+
+	Describe("Goify with a single initialism", func() {
+		It("produces the initialism in the requested case", func() {
+			for _, i := range codegen.CommonInitialisms() {
+				lower := strings.ToLower(i)
+				for _, str := range []string{i, lower, i[:1] + lower[1:]} {
+					for _, o := range goifyCombinations() {
+						desc := fmt.Sprintf("GoifyWith(%#v, %+v)", str, o)
+						actual := codegen.GoifyWith(str, o)
+						if o.FirstUpper {
+							Ω(actual).Should(Equal(i), desc)
+							continue
+						}
+						if actual == lower+"_" {
+							// reserved words such as "http" are suffixed
+							_, err := codegen.GoifyStrict(lower, false)
+							Ω(err).Should(HaveOccurred(), desc)
+							continue
+						}
+						Ω(actual).Should(Equal(lower), desc)
+					}
+				}
+			}
+		})
+	})
+
+	Describe("GoifyInitialisms", func() {
+		It("splits initialisms that start lowercase words", func() {
+			Ω(codegen.GoifyInitialisms("apikey", true)).Should(Equal("APIKey"))
+			Ω(codegen.GoifyInitialisms("httpserver", true)).Should(Equal("HTTPServer"))
+			Ω(codegen.GoifyInitialisms("jsondata", true)).Should(Equal("JSONData"))
+			Ω(codegen.GoifyInitialisms("apikey", false)).Should(Equal("apiKey"))
+		})
+
+		It("does not split words that are initialisms", func() {
+			Ω(codegen.GoifyInitialisms("https", true)).Should(Equal("HTTPS"))
+			Ω(codegen.GoifyInitialisms("user_id", true)).Should(Equal("UserID"))
+		})
+
+		It("is opt-in", func() {
+			Ω(codegen.Goify("apikey", true)).Should(Equal("Apikey"))
+		})
+	})
+
+	Describe("GoifyWith", func() {
+		It("behaves like Goify with the default options", func() {
+			Ω(codegen.GoifyWith("foo_bar", codegen.GoifyOptions{})).Should(Equal(codegen.Goify("foo_bar", false)))
+			Ω(codegen.GoifyWith("foo_bar", codegen.GoifyOptions{FirstUpper: true})).Should(Equal("FooBar"))
+		})
+
+		It("splits initialisms when requested", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, SplitInitialisms: true}
+			Ω(codegen.GoifyWith("apikey", opts)).Should(Equal("APIKey"))
+		})
+
+		It("splits words after digits when requested", func() {
+			Ω(codegen.GoifyWith("http2server", codegen.GoifyOptions{FirstUpper: true})).Should(Equal("HTTP2server"))
+			opts := codegen.GoifyOptions{FirstUpper: true, SplitInitialisms: true, SplitDigits: true}
+			Ω(codegen.GoifyWith("http2server", opts)).Should(Equal("HTTP2Server"))
+			Ω(codegen.GoifyWith("oauth2token", opts)).Should(Equal("Oauth2Token"))
+			opts.FirstUpper = false
+			Ω(codegen.GoifyWith("http2server", opts)).Should(Equal("http2Server"))
+		})
+
+		It("uses overrides verbatim", func() {
+			opts := codegen.GoifyOptions{FirstUpper: true, Overrides: map[string]string{"id_legacy": "LegacyID"}}
+			Ω(codegen.GoifyWith("id_legacy", opts)).Should(Equal("LegacyID"))
+			Ω(codegen.GoifyWith("id_new", opts)).Should(Equal("IDNew"))
+		})
+	})
+
+	Describe("GoifyMethod", func() {
+		It("produces exported names", func() {
+			Ω(codegen.GoifyMethod("show")).Should(Equal("Show"))
+			Ω(codegen.GoifyMethod("get_by_id")).Should(Equal("GetByID"))
+			Ω(codegen.GoifyMethod("_type")).Should(Equal("Type"))
+		})
+
+		It("prefixes names that cannot be exported otherwise", func() {
+			Ω(codegen.GoifyMethod("2fa")).Should(Equal("X2fa"))
+			Ω(codegen.GoifyMethod("日本")).Should(Equal("X日本"))
+			Ω(codegen.GoifyMethod("%")).Should(Equal("X"))
+		})
+
+		It("is idempotent", func() {
+			Ω(codegen.GoifyMethod(codegen.GoifyMethod("2fa"))).Should(Equal("X2fa"))
+		})
+	})
+
+	Describe("GoifyStrict", func() {
+		It("produces the same identifiers as Goify", func() {
+			for _, str := range []string{"user_id", "Type", "x2fa", "O'Brien"} {
+				name, err := codegen.GoifyStrict(str, true)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(name).Should(Equal(codegen.Goify(str, true)))
+			}
+		})
+
+		It("rejects strings that Goify mangles", func() {
+			for _, str := range []string{"", "%-$", "2fa", "_type", "range"} {
+				_, err := codegen.GoifyStrict(str, false)
+				Ω(err).Should(HaveOccurred(), str)
+			}
+			_, err := codegen.GoifyStrict("type", true)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("SplitWords", func() {
+		It("splits on separators and case changes", func() {
+			Ω(codegen.SplitWords("user_id")).Should(Equal([]string{"user", "id"}))
+			Ω(codegen.SplitWords("createdAt--Time")).Should(Equal([]string{"created", "At", "Time"}))
+			Ω(codegen.SplitWords("getHTTPResponse")).Should(Equal([]string{"get", "HTTPResponse"}))
+		})
+
+		It("drops invalid characters", func() {
+			Ω(codegen.SplitWords("%foo bar%")).Should(Equal([]string{"foo", "bar"}))
+			Ω(codegen.SplitWords("%%")).Should(BeEmpty())
+		})
+
+		It("returns words owned by the caller", func() {
+			words := codegen.SplitWords("utf8_id_%_name")
+			Ω(words).Should(Equal([]string{"utf8", "id", "name"}))
+			words[0], words[1] = "foo", "bar"
+			Ω(codegen.SplitWords("utf8_id_%_name")).Should(Equal([]string{"utf8", "id", "name"}))
+			Ω(codegen.SplitWords("utf8_id_%_name§")).Should(Equal([]string{"utf8", "id", "name"}))
+		})
+	})
+
+	Describe("GoPackageName", func() {
+		var str, name string
+
+		JustBeforeEach(func() {
+			name = codegen.GoPackageName(str)
+		})
+
+		Context("given a mixed case string with separators", func() {
+			BeforeEach(func() {
+				str = "Bottle_Service-v1"
+			})
+			It("lowercases and removes the separators", func() {
+				Ω(name).Should(Equal("bottleservicev1"))
+			})
+		})
+
+		Context("given a string starting with a digit", func() {
+			BeforeEach(func() {
+				str = "2fa"
+			})
+			It("prefixes the name", func() {
+				Ω(name).Should(Equal("pkg2fa"))
+			})
+		})
+
+		Context("given a reserved word", func() {
+			BeforeEach(func() {
+				str = "Type"
+			})
+			It("suffixes the name", func() {
+				Ω(name).Should(Equal("typepkg"))
+			})
+		})
+
+		Context("given a string with no letter or digit", func() {
+			BeforeEach(func() {
+				str = "_-"
+			})
+			It("falls back to a valid name", func() {
+				Ω(name).Should(Equal("pkg"))
+			})
+		})
+	})
+
+	Describe("GoNativeType", func() {
+		Context("given hashes of any", func() {
+			hashOf := func(key, elem DataType) *Hash {
+				return &Hash{KeyType: &AttributeDefinition{Type: key}, ElemType: &AttributeDefinition{Type: elem}}
+			}
+
+			It("produces interface{} values", func() {
+				Ω(codegen.GoNativeType(hashOf(String, Any))).Should(Equal("map[string]interface{}"))
+				Ω(codegen.GoNativeType(hashOf(Integer, Any))).Should(Equal("map[int]interface{}"))
+				Ω(codegen.GoTypeName(hashOf(String, Any), nil, 0, false)).Should(Equal("map[string]interface{}"))
+			})
+
+			It("rejects any keys", func() {
+				Ω(func() { codegen.GoNativeType(hashOf(Any, String)) }).Should(Panic())
+				Ω(func() { codegen.GoTypeName(hashOf(Any, String), nil, 0, false) }).Should(Panic())
+			})
+		})
+
+		Context("given a big integer", func() {
+			It("produces a big.Int pointer", func() {
+				Ω(codegen.GoNativeType(BigInt)).Should(Equal("*big.Int"))
+			})
+		})
+
+		Context("given a duration", func() {
+			It("produces a goa.Duration", func() {
+				Ω(codegen.GoNativeType(Duration)).Should(Equal("goa.Duration"))
+			})
+
+			It("produces a pointer when the attribute is optional", func() {
+				o := Object{"timeout": &AttributeDefinition{Type: Duration}}
+				Ω(codegen.GoTypeDef(&AttributeDefinition{Type: o}, 0, true, false)).Should(ContainSubstring("Timeout *goa.Duration"))
+			})
+		})
+
+		Context("given raw JSON", func() {
+			It("produces a json.RawMessage", func() {
+				Ω(codegen.GoNativeType(RawJSON)).Should(Equal("json.RawMessage"))
+			})
+
+			It("does not produce a pointer when the attribute is optional", func() {
+				o := Object{"payload": &AttributeDefinition{Type: RawJSON}}
+				Ω(codegen.GoTypeDef(&AttributeDefinition{Type: o}, 0, true, false)).Should(ContainSubstring("Payload json.RawMessage"))
+			})
+		})
+	})
+
+	Describe("KindFromGoType", func() {
+		It("inverts GoNativeType for primitives", func() {
+			for _, p := range []Primitive{Boolean, Integer, Number, String, DateTime, UUID, Any, BigInt, Duration, RawJSON} {
+				kind, ok := codegen.KindFromGoType(codegen.GoNativeType(p))
+				Ω(ok).Should(BeTrue())
+				Ω(kind).Should(Equal(p.Kind()))
+			}
+		})
+
+		It("rejects other types", func() {
+			for _, t := range []string{"int32", "[]string", "map[string]interface{}", "Bottle", ""} {
+				_, ok := codegen.KindFromGoType(t)
+				Ω(ok).Should(BeFalse())
+			}
+		})
+	})
+
+	Describe("IsComparable", func() {
+		It("supports primitives", func() {
+			Ω(codegen.IsComparable(String)).Should(BeTrue())
+			Ω(codegen.IsComparable(DateTime)).Should(BeTrue())
+			Ω(codegen.IsComparable(Any)).Should(BeFalse())
+		})
+
+		It("does not support arrays and hashes", func() {
+			Ω(codegen.IsComparable(&Array{ElemType: &AttributeDefinition{Type: String}})).Should(BeFalse())
+			hash := &Hash{KeyType: &AttributeDefinition{Type: String}, ElemType: &AttributeDefinition{Type: String}}
+			Ω(codegen.IsComparable(hash)).Should(BeFalse())
+		})
+
+		It("supports objects made of comparable fields", func() {
+			Ω(codegen.IsComparable(Object{"foo": &AttributeDefinition{Type: Integer}})).Should(BeTrue())
+			tags := &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: String}}}
+			Ω(codegen.IsComparable(Object{"foo": &AttributeDefinition{Type: Integer}, "tags": tags})).Should(BeFalse())
+		})
+
+		It("supports recursive user types", func() {
+			ut := &UserTypeDefinition{TypeName: "node"}
+			ut.AttributeDefinition = &AttributeDefinition{Type: Object{
+				"name": &AttributeDefinition{Type: String},
+				"next": &AttributeDefinition{Type: ut},
+			}}
+			Ω(codegen.IsComparable(ut)).Should(BeTrue())
+		})
+	})
+
+	Describe("GoMethodSignature", func() {
+		var payload, result *UserTypeDefinition
+
+		BeforeEach(func() {
+			payload = &UserTypeDefinition{
+				TypeName:            "ShowPayload",
+				AttributeDefinition: &AttributeDefinition{Type: Object{}},
+			}
+			result = &UserTypeDefinition{
+				TypeName:            "bottle",
+				AttributeDefinition: &AttributeDefinition{Type: Object{}},
+			}
+		})
+
+		It("produces the signature with payload and result", func() {
+			Ω(codegen.GoMethodSignature("show", payload, result)).
+				Should(Equal("Show(ctx context.Context, p *ShowPayload) (*Bottle, error)"))
+		})
+
+		It("handles methods with no payload or no result", func() {
+			Ω(codegen.GoMethodSignature("list", nil, &Array{ElemType: &AttributeDefinition{Type: result}})).
+				Should(Equal("List(ctx context.Context) ([]*Bottle, error)"))
+			Ω(codegen.GoMethodSignature("delete", payload, nil)).
+				Should(Equal("Delete(ctx context.Context, p *ShowPayload) error"))
+			var noPayload *UserTypeDefinition
+			Ω(codegen.GoMethodSignature("delete", noPayload, nil)).
+				Should(Equal("Delete(ctx context.Context) error"))
+		})
+	})
+
+	Describe("GoTypeRef", func() {
+		Context("given arrays", func() {
+			var elem *UserTypeDefinition
+			arrayOf := func(t DataType) *Array {
+				return &Array{ElemType: &AttributeDefinition{Type: t}}
+			}
+			hashOf := func(t DataType) *Hash {
+				return &Hash{KeyType: &AttributeDefinition{Type: String}, ElemType: &AttributeDefinition{Type: t}}
+			}
+
+			BeforeEach(func() {
+				elem = &UserTypeDefinition{
+					TypeName:            "elem",
+					AttributeDefinition: &AttributeDefinition{Type: Object{"foo": &AttributeDefinition{Type: String}}},
+				}
+			})
+
+			It("does not use pointers for primitive elements", func() {
+				Ω(codegen.GoTypeRef(arrayOf(String), nil, 0, false)).Should(Equal("[]string"))
+				Ω(codegen.GoTypeRef(arrayOf(Integer), nil, 0, true)).Should(Equal("[]int"))
+			})
+
+			It("uses pointers for object elements", func() {
+				Ω(codegen.GoTypeRef(arrayOf(elem), nil, 0, false)).Should(Equal("[]*Elem"))
+				Ω(codegen.GoTypeRef(arrayOf(elem), nil, 0, true)).Should(Equal("[]*elem"))
+				Ω(codegen.GoTypeRef(arrayOf(Object{"foo": &AttributeDefinition{Type: String}}), nil, 0, false)).
+					Should(Equal("[]*struct {\n\tFoo *string\n}"))
+			})
+
+			It("uses pointers for the innermost elements of nested arrays", func() {
+				Ω(codegen.GoTypeRef(arrayOf(arrayOf(elem)), nil, 0, false)).Should(Equal("[][]*Elem"))
+				Ω(codegen.GoTypeRef(arrayOf(arrayOf(String)), nil, 0, false)).Should(Equal("[][]string"))
+			})
+
+			It("uses pointers for object elements of maps", func() {
+				Ω(codegen.GoTypeRef(arrayOf(hashOf(elem)), nil, 0, false)).Should(Equal("[]map[string]*Elem"))
+				Ω(codegen.GoTypeRef(hashOf(arrayOf(elem)), nil, 0, false)).Should(Equal("map[string][]*Elem"))
+			})
+
+			It("matches the type definitions", func() {
+				for _, t := range []DataType{arrayOf(elem), arrayOf(arrayOf(elem)), arrayOf(hashOf(elem)), hashOf(arrayOf(elem))} {
+					Ω(codegen.GoTypeDef(&AttributeDefinition{Type: t}, 0, true, false)).
+						Should(Equal(codegen.GoTypeRef(t, nil, 0, false)))
+				}
+			})
+		})
+	})
+
+	Describe("ShouldOmitEmpty", func() {
+		var parent *AttributeDefinition
+
+		BeforeEach(func() {
+			parent = &AttributeDefinition{
+				Type: Object{
+					"name":    &AttributeDefinition{Type: String},
+					"rating":  &AttributeDefinition{Type: Integer},
+					"vintage": &AttributeDefinition{Type: Integer, DefaultValue: 2000},
+					"sweet":   &AttributeDefinition{Type: Boolean, Metadata: dslengine.MetadataDefinition{"struct:field:omitempty": {"false"}}},
+					"tags":    &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: String}}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			}
+		})
+
+		It("omits optional fields whose empty value means unset", func() {
+			Ω(codegen.ShouldOmitEmpty(parent, "rating", false, codegen.PointerFields)).Should(BeTrue())
+			Ω(codegen.ShouldOmitEmpty(parent, "tags", false, codegen.BitmapFields)).Should(BeTrue())
+		})
+
+		It("keeps required fields and fields with a default value", func() {
+			Ω(codegen.ShouldOmitEmpty(parent, "name", false, codegen.PointerFields)).Should(BeFalse())
+			Ω(codegen.ShouldOmitEmpty(parent, "vintage", false, codegen.PointerFields)).Should(BeFalse())
+		})
+
+		It("keeps optional primitive fields generated as values", func() {
+			Ω(codegen.ShouldOmitEmpty(parent, "rating", false, codegen.BitmapFields)).Should(BeFalse())
+			st := codegen.GoTypeDefMode(parent, 0, true, false, codegen.BitmapFields)
+			Ω(st).Should(ContainSubstring("Rating int `json:\"rating\" xml:\"rating\"`"))
+		})
+
+		It("keeps optional primitive fields generated as Optional", func() {
+			Ω(codegen.ShouldOmitEmpty(parent, "rating", false, codegen.OptionalFields)).Should(BeFalse())
+			Ω(codegen.ShouldOmitEmpty(parent, "tags", false, codegen.OptionalFields)).Should(BeTrue())
+		})
+
+		It("keeps fields flagged with metadata", func() {
+			Ω(codegen.ShouldOmitEmpty(parent, "sweet", false, codegen.PointerFields)).Should(BeFalse())
+			st := codegen.GoTypeDef(parent, 0, true, false)
+			Ω(st).Should(ContainSubstring("Sweet *bool `json:\"sweet\" xml:\"sweet\"`"))
+		})
+	})
+
+	Describe("GoTypeDef with embedded types", func() {
+		var att *AttributeDefinition
+
+		BeforeEach(func() {
+			base := &UserTypeDefinition{
+				TypeName: "Audit",
+				AttributeDefinition: &AttributeDefinition{Type: Object{
+					"created_at": &AttributeDefinition{Type: DateTime},
+				}},
+			}
+			att = &AttributeDefinition{
+				Type: Object{
+					"created_at": &AttributeDefinition{Type: DateTime},
+					"name":       &AttributeDefinition{Type: String},
+				},
+				Embedded: []*UserTypeDefinition{base},
+			}
+		})
+
+		It("embeds the type and skips its attributes", func() {
+			Ω(codegen.GoTypeDef(att, 0, false, false)).Should(Equal("struct {\n\tAudit\n\tName *string\n}"))
+		})
+	})
+
+	Describe("GoTypeDef with recursive types", func() {
+		var node, parent *UserTypeDefinition
+
+		BeforeEach(func() {
+			node = &UserTypeDefinition{TypeName: "Node"}
+			parent = &UserTypeDefinition{TypeName: "Parent"}
+			node.AttributeDefinition = &AttributeDefinition{
+				Type: Object{
+					"next":   &AttributeDefinition{Type: node},
+					"parent": &AttributeDefinition{Type: parent},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"next", "parent"}},
+			}
+			parent.AttributeDefinition = &AttributeDefinition{
+				Type:       Object{"child": &AttributeDefinition{Type: node}},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"child"}},
+			}
+		})
+
+		It("uses pointers for required recursive fields", func() {
+			Ω(codegen.GoTypeDef(node, 0, false, false)).Should(Equal("struct {\n\tNext *Node\n\tParent *Parent\n}"))
+			Ω(codegen.GoTypeDef(parent, 0, false, false)).Should(Equal("struct {\n\tChild *Node\n}"))
+		})
+
+		It("keeps pointers for recursive types in ValueFields mode", func() {
+			Ω(codegen.GoTypeDefMode(node, 0, false, false, codegen.ValueFields)).Should(Equal("struct {\n\tNext *Node\n\tParent *Parent\n}"))
+			Ω(codegen.GoTypeRefMode(node, nil, 0, false, codegen.ValueFields)).Should(Equal("*Node"))
+		})
+	})
+
+	Describe("GoTypeDefMode with value fields", func() {
+		var bottle *UserTypeDefinition
+
+		BeforeEach(func() {
+			origin := &UserTypeDefinition{
+				TypeName:            "Origin",
+				AttributeDefinition: &AttributeDefinition{Type: Object{"country": &AttributeDefinition{Type: String}}},
+			}
+			bottle = &UserTypeDefinition{
+				TypeName: "Bottle",
+				AttributeDefinition: &AttributeDefinition{
+					Type: Object{
+						"name":   &AttributeDefinition{Type: String},
+						"origin": &AttributeDefinition{Type: origin},
+						"others": &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: origin}}},
+					},
+				},
+			}
+		})
+
+		It("uses values for optional primitives and objects", func() {
+			Ω(codegen.GoTypeDefMode(bottle, 0, false, false, codegen.ValueFields)).
+				Should(Equal("struct {\n\tName string\n\tOrigin Origin\n\tOthers []Origin\n}"))
+			Ω(codegen.GoTypeRefMode(bottle, nil, 0, false, codegen.ValueFields)).Should(Equal("Bottle"))
+		})
+
+		It("does not change the default mode", func() {
+			Ω(codegen.GoTypeDef(bottle, 0, false, false)).
+				Should(Equal("struct {\n\tName *string\n\tOrigin *Origin\n\tOthers []*Origin\n}"))
+			Ω(codegen.GoTypeRef(bottle, nil, 0, false)).Should(Equal("*Bottle"))
+		})
+	})
+
+	Describe("GoTypeDefFieldComments", func() {
+		var att *AttributeDefinition
+
+		BeforeEach(func() {
+			att = &AttributeDefinition{
+				Type: Object{
+					"id":          &AttributeDefinition{Type: Integer},
+					"description": &AttributeDefinition{Type: String},
+					"name": &AttributeDefinition{
+						Type:     String,
+						Metadata: dslengine.MetadataDefinition{"struct:tag:json": {"full_name,omitempty"}},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+			}
+		})
+
+		It("aligns the field comments", func() {
+			Ω(codegen.GoTypeDefFieldComments(att, 0, false, false, codegen.PointerFields)).Should(Equal("struct {\n" +
+				"\tDescription *string // json:\"description\" (optional)\n" +
+				"\tID int              // json:\"id\" (required)\n" +
+				"\tName *string        // json:\"full_name\" (optional)\n" +
+				"}"))
+		})
+
+		It("does not change GoTypeDef", func() {
+			Ω(codegen.GoTypeDef(att, 0, false, false)).Should(Equal("struct {\n\tDescription *string\n\tID int\n\tName *string\n}"))
+		})
+	})
+
+	Describe("GoTypeDef with deprecated fields", func() {
+		var att *AttributeDefinition
+
+		BeforeEach(func() {
+			att = &AttributeDefinition{
+				Type: Object{
+					"name": &AttributeDefinition{
+						Type:     String,
+						Metadata: dslengine.MetadataDefinition{"struct:field:deprecated": {"use FullName instead."}},
+					},
+				},
+			}
+		})
+
+		It("writes the deprecation notice above the field", func() {
+			Ω(codegen.GoTypeDef(att, 0, false, false)).Should(Equal("struct {\n" +
+				"\t// Deprecated: use FullName instead.\n" +
+				"\tName *string\n" +
+				"}"))
+		})
+
+		It("separates the notice from the description", func() {
+			att.Type.ToObject()["name"].Description = "Name of the bottle"
+			Ω(codegen.GoTypeDef(att, 0, false, false)).Should(Equal("struct {\n" +
+				"\t// Name of the bottle\n" +
+				"\t//\n" +
+				"\t// Deprecated: use FullName instead.\n" +
+				"\tName *string\n" +
+				"}"))
+		})
+
+		It("writes a default reason", func() {
+			att.Type.ToObject()["name"].Metadata["struct:field:deprecated"] = nil
+			Ω(codegen.GoTypeDef(att, 0, false, false)).Should(ContainSubstring(
+				"\t// Deprecated: this field should not be used.\n\tName *string\n"))
+		})
+	})
+
 	Describe("GoTypeDef", func() {
 		Context("given an attribute definition with fields", func() {
 			var att *AttributeDefinition
@@ -178,6 +946,25 @@ var _ = Describe("code generation", func() {
 					Ω(st).Should(Equal(expected))
 				})
 
+				Context("including big integers", func() {
+					BeforeEach(func() {
+						object = Object{
+							"id":  &AttributeDefinition{Type: BigInt},
+							"ref": &AttributeDefinition{Type: BigInt},
+						}
+						required = &dslengine.ValidationDefinition{Required: []string{"id"}}
+					})
+
+					It("does not add a pointer to the big.Int pointer", func() {
+						expected := "struct {\n" +
+							"	ID *big.Int `json:\"id\" xml:\"id\"`\n" +
+							"	Ref *big.Int `json:\"ref,omitempty\" xml:\"ref,omitempty\"`\n" +
+							"}"
+						Ω(st).Should(Equal(expected))
+						Ω(codegen.GoTypeDef(att, 0, true, true)).Should(ContainSubstring("	ID *big.Int `"))
+					})
+				})
+
 				Context("using struct tags metadata", func() {
 					tn1 := "struct:tag:foo"
 					tv11 := "bar"
@@ -340,6 +1127,40 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("using the optional fields mode", func() {
+				BeforeEach(func() {
+					object = Object{
+						"foo": &AttributeDefinition{Type: Integer},
+						"bar": &AttributeDefinition{Type: String},
+						"baz": &AttributeDefinition{Type: Object{"qux": &AttributeDefinition{Type: Boolean}}},
+					}
+					required = &dslengine.ValidationDefinition{
+						Required: []string{"foo"},
+					}
+				})
+
+				JustBeforeEach(func() {
+					st = codegen.GoTypeDefMode(att, 0, true, false, codegen.OptionalFields)
+				})
+
+				It("uses Optional for the optional primitive fields", func() {
+					expected := "struct {\n" +
+						"	Bar Optional[string] `json:\"bar\" xml:\"bar\"`\n" +
+						"	Baz *struct {\n" +
+						"		Qux Optional[bool] `json:\"qux\" xml:\"qux\"`\n" +
+						"	} `json:\"baz,omitempty\" xml:\"baz,omitempty\"`\n" +
+						"	Foo int `json:\"foo\" xml:\"foo\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+
+				It("reads the optional fields with Get", func() {
+					Ω(codegen.GoFieldValue(att, "bar", "ut.Bar", false, codegen.OptionalFields)).Should(Equal("ut.Bar.Get()"))
+					Ω(codegen.GoFieldValue(att, "bar", "ut.Bar", false, codegen.PointerFields)).Should(Equal("*ut.Bar"))
+					Ω(codegen.GoFieldValue(att, "foo", "ut.Foo", false, codegen.OptionalFields)).Should(Equal("ut.Foo"))
+				})
+			})
+
 		})
 
 		Context("given an array", func() {
@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var validatingConstructorT *template.Template
+
+// init instantiates the templates.
+func init() {
+	var err error
+	if validatingConstructorT, err = template.New("validatingConstructor").Parse(validatingConstructorTmpl); err != nil {
+		panic(err)
+	}
+}
+
+// constructorParam describes a parameter of a generated constructor.
+type constructorParam struct {
+	// Field is the name of the struct field initialized with the parameter.
+	Field string
+	// Param is the name of the parameter.
+	Param string
+	// Type is the Go type of the parameter, that is the type of the field.
+	Type string
+}
+
+// GoValidatingConstructor produces the Go code of the constructor of the struct generated for the
+// given object user type that takes the values of the required attributes, e.g.
+// `NewBottle(id int, name string) (*Bottle, error)`. The constructor calls the Validate method
+// generated for the type (see RecursiveChecker) and returns the validation error if any so that
+// invalid instances cannot be built with it. The optional fields are left unset. The constructor
+// returns a nil error if the type defines no validation as no Validate method is generated then.
+func GoValidatingConstructor(ut *design.UserTypeDefinition) string {
+	if !ut.IsObject() {
+		panic("goa bug: validating constructors require an object user type")
+	}
+	att := ut.AttributeDefinition
+	var params []*constructorParam
+	taken := map[string]bool{"ut": true, "err": true}
+	att.Type.ToObject().IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if !att.IsRequired(n) {
+			return nil
+		}
+		field := goFieldName(n, catt)
+		typedef := goTypeName(catt.Type, catt.AllRequired(), 1, false, PointerFields, nil)
+		params = append(params, &constructorParam{
+			Field: field,
+			Param: uniqueName(Goify(field, false), taken),
+			Type:  fieldTypeRef(att, n, typedef, false, PointerFields),
+		})
+		return nil
+	})
+	validation := RecursiveChecker(att, false, false, false, "ut", "response", 1, false)
+	data := map[string]interface{}{
+		"Name":     GoTypeName(ut, nil, 0, false),
+		"Params":   params,
+		"Validate": validation != "",
+	}
+	return RunTemplate(validatingConstructorT, data)
+}
+
+const validatingConstructorTmpl = `{{ $name := .Name }}// New{{ $name }} returns a {{ $name }} initialized with the given required values{{ if .Validate }} or
+// the error returned by its Validate method if the values are invalid{{ end }}.
+func New{{ $name }}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Param }} {{ $p.Type }}{{ end }}) (*{{ $name }}, error) {
+	ut := &{{ $name }}{
+{{ range .Params }}		{{ .Field }}: {{ .Param }},
+{{ end }}	}
+{{ if .Validate }}	if err := ut.Validate(); err != nil {
+		return nil, err
+	}
+{{ end }}	return ut, nil
+}
+`
@@ -0,0 +1,69 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoValidatingConstructor", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Bottle",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":     &design.AttributeDefinition{Type: design.Integer},
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name", "id"}},
+			},
+		}
+	})
+
+	It("takes the required values and validates the instance", func() {
+		Ω(codegen.GoValidatingConstructor(ut)).Should(Equal(validatingBottleCode))
+	})
+
+	It("does not validate types that define no validation", func() {
+		ut.Validation = nil
+		Ω(codegen.GoValidatingConstructor(ut)).Should(Equal(unvalidatedBottleCode))
+	})
+
+	It("passes required objects by pointer", func() {
+		ut.Type.ToObject()["label"] = &design.AttributeDefinition{Type: &design.UserTypeDefinition{
+			TypeName:            "Label",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}}
+		ut.Validation.Required = append(ut.Validation.Required, "label")
+		code := codegen.GoValidatingConstructor(ut)
+		Ω(code).Should(ContainSubstring("func NewBottle(id int, label *Label, name string) (*Bottle, error) {"))
+		Ω(code).Should(ContainSubstring("\t\tLabel: label,\n"))
+	})
+})
+
+const validatingBottleCode = `// NewBottle returns a Bottle initialized with the given required values or
+// the error returned by its Validate method if the values are invalid.
+func NewBottle(id int, name string) (*Bottle, error) {
+	ut := &Bottle{
+		ID: id,
+		Name: name,
+	}
+	if err := ut.Validate(); err != nil {
+		return nil, err
+	}
+	return ut, nil
+}
+`
+
+const unvalidatedBottleCode = `// NewBottle returns a Bottle initialized with the given required values.
+func NewBottle() (*Bottle, error) {
+	ut := &Bottle{
+	}
+	return ut, nil
+}
+`
@@ -2,6 +2,8 @@ package codegen
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -16,6 +18,7 @@ var (
 	patternValT  *template.Template
 	minMaxValT   *template.Template
 	lengthValT   *template.Template
+	uniqueValT   *template.Template
 	requiredValT *template.Template
 )
 
@@ -25,11 +28,13 @@ func init() {
 	fm := template.FuncMap{
 		"tabs":             Tabs,
 		"slice":            toSlice,
-		"oneof":            oneof,
+		"enumCases":        enumCases,
 		"constant":         constant,
 		"goify":            Goify,
 		"add":              Add,
 		"recursiveChecker": RecursiveChecker,
+		"patternVar":       PatternVarName,
+		"bigIntBound":      bigIntBound,
 	}
 	if arrayValT, err = template.New("array").Funcs(fm).Parse(arrayValTmpl); err != nil {
 		panic(err)
@@ -49,6 +54,9 @@ func init() {
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
+	if uniqueValT, err = template.New("unique").Funcs(fm).Parse(uniqueValTmpl); err != nil {
+		panic(err)
+	}
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
@@ -124,16 +132,26 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 // validation error.
 // The generated code assumes that there is a pre-existing "err" variable of type
 // error. It initializes that variable in case a validation fails.
+// Enum validations switch over the allowed values, the default case records the error.
+// Pattern validations refer to the package level variables declared by GoPatternVars.
+// Unique items validations compare the items of arrays with goa.DuplicateItem.
+// RawJSON values are not decoded and thus not validated.
 // Note: we do not want to recurse here, recursion is done by the marshaler/unmarshaler code.
 func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
+	if att.Type.Kind() == design.RawJSONKind {
+		return ""
+	}
 	t := target
+	bigInt := att.Type.Kind() == design.BigIntKind
 	isPointer := private || (!required && !hasDefault && !nonzero)
-	if isPointer && att.Type.IsPrimitive() {
+	if isPointer && att.Type.IsPrimitive() && !bigInt {
 		t = "*" + t
 	}
 	data := map[string]interface{}{
 		"attribute": att,
-		"isPointer": private || isPointer,
+		"bigInt":    bigInt,
+		"duration":  att.Type.Kind() == design.DurationKind,
+		"isPointer": private || isPointer || bigInt,
 		"nonzero":   nonzero,
 		"context":   context,
 		"target":    target,
@@ -201,6 +219,11 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if validation.UniqueItems && data["array"] == true {
+		if val := RunTemplate(uniqueValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if required := validation.Required; len(required) > 0 {
 		data["required"] = required
 		if val := RunTemplate(requiredValT, data); val != "" {
@@ -210,14 +233,32 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	return
 }
 
-// oneof produces code that compares target with each element of vals and ORs
-// the result, e.g. "target == 1 || target == 2".
-func oneof(target string, vals []interface{}) string {
-	elems := make([]string, len(vals))
-	for i, v := range vals {
-		elems[i] = fmt.Sprintf("%s == %#v", target, v)
+// enumCases produces the comma separated list of the Go literals of vals used as the case of the
+// switch that validates enums. Values that produce the same literal are only listed once as
+// duplicate cases do not compile.
+func enumCases(vals []interface{}) string {
+	seen := make(map[string]bool, len(vals))
+	elems := make([]string, 0, len(vals))
+	for _, v := range vals {
+		lit := fmt.Sprintf("%#v", v)
+		if !seen[lit] {
+			seen[lit] = true
+			elems = append(elems, lit)
+		}
 	}
-	return strings.Join(elems, " || ")
+	return strings.Join(elems, ", ")
+}
+
+// bigIntBound renders the minimum or maximum value of a big integer validation as an exact decimal
+// integer, the float64 bound is rounded up for minimums and down for maximums so that the integers
+// that pass the validation are the same.
+func bigIntBound(bound float64, min bool) string {
+	if min {
+		bound = math.Ceil(bound)
+	} else {
+		bound = math.Floor(bound)
+	}
+	return strconv.FormatFloat(bound, 'f', 0, 64)
 }
 
 // constant returns the Go constant name of the format with the given value.
@@ -253,14 +294,16 @@ const (
 
 	enumValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
-{{end}}{{tabs $depth}}if !({{oneof .targetVal .values}}) {
+{{end}}{{tabs $depth}}switch {{.targetVal}} {
+{{tabs $depth}}case {{enumCases .values}}:
+{{tabs $depth}}default:
 {{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, {{slice .values}}))
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
 	patternValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
-{{end}}{{tabs $depth}}if ok := goa.ValidatePattern(` + "`{{.pattern}}`" + `, {{.targetVal}}); !ok {
+{{end}}{{tabs $depth}}if ok := {{patternVar .pattern}}.MatchString({{.targetVal}}); !ok {
 {{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, ` + "`{{.pattern}}`" + `))
 {{tabs $depth}}}{{if .isPointer}}
 {{tabs .depth}}}{{end}}`
@@ -274,7 +317,8 @@ const (
 
 	minMaxValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
-{{end}}{{tabs .depth}}	if {{.targetVal}} {{if .isMin}}<{{else}}>{{end}} {{if .isMin}}{{.min}}{{else}}{{.max}}{{end}} {
+{{end}}{{tabs .depth}}	if {{if .bigInt}}bound, _ := new(big.Int).SetString("{{if .isMin}}{{bigIntBound .min true}}{{else}}{{bigIntBound .max false}}{{end}}", 10); {{end}}{{/*
+*/}}{{.targetVal}}{{if .bigInt}}.Cmp(bound){{end}} {{if .isMin}}<{{else}}>{{end}} {{if .bigInt}}0{{else if .duration}}goa.Duration({{if .isMin}}{{.min}}{{else}}{{.max}}{{end}}){{else}}{{if .isMin}}{{.min}}{{else}}{{.max}}{{end}}{{end}} {
 {{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, {{if .isMin}}{{.min}}, true{{else}}{{.max}}, false{{end}}))
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
@@ -287,11 +331,27 @@ const (
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
+	uniqueValTmpl = `{{tabs .depth}}if dup, ok := goa.DuplicateItem({{.target}}); ok {
+{{tabs .depth}}	err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `{{.context}}` + "`" + `, dup))
+{{tabs .depth}}}`
+
 	requiredValTmpl = `{{range $r := .required}}{{$catt := index $.attribute.Type.ToObject $r}}{{/*
-*/}}{{if and (not $.private) (eq $catt.Type.Kind 4)}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == "" {
+*/}}{{if and (not $.private) (eq $catt.Type.Kind 4)}}{{/*
+
+*/}}{{/* StringKind */}}{{/*
+*/}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == "" {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
-{{else if or $.private (not $catt.Type.IsPrimitive)}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == nil {
+{{else if eq $catt.Type.Kind 15}}{{/*
+
+*/}}{{/* RawJSONKind, never a pointer */}}{{/*
+*/}}{{tabs $.depth}}if len({{$.target}}.{{goify $r true}}) == 0 {
+{{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
+{{tabs $.depth}}}
+{{else if or (or $.private (not $catt.Type.IsPrimitive)) (eq $catt.Type.Kind 13)}}{{/*
+
+*/}}{{/* pointers and BigIntKind */}}{{/*
+*/}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == nil {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
 {{end}}{{end}}`
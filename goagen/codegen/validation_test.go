@@ -1,6 +1,8 @@
 package codegen_test
 
 import (
+	"time"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
@@ -42,6 +44,20 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of string enum with duplicate values", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Values: []interface{}{"red", "white", "red"},
+					}
+				})
+
+				It("lists each value once in the switch case", func() {
+					Ω(code).Should(ContainSubstring("\t\tswitch *val {\n\t\tcase \"red\", \"white\":\n\t\tdefault:\n"))
+					Ω(code).Should(ContainSubstring(`[]interface{}{"red", "white", "red"}`))
+				})
+			})
+
 			Context("of pattern", func() {
 				BeforeEach(func() {
 					attType = design.String
@@ -69,6 +85,61 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of min value 0 on a big integer", func() {
+				BeforeEach(func() {
+					attType = design.BigInt
+					min := 0.0
+					validation = &dslengine.ValidationDefinition{
+						Minimum: &min,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(bigIntMinValCode))
+				})
+			})
+
+			Context("of max value 1e15 on a big integer", func() {
+				BeforeEach(func() {
+					attType = design.BigInt
+					max := 1e15
+					validation = &dslengine.ValidationDefinition{
+						Maximum: &max,
+					}
+				})
+
+				It("renders the bound as an exact integer", func() {
+					Ω(code).Should(Equal(bigIntMaxValCode))
+				})
+			})
+
+			Context("of min value 1s on a duration", func() {
+				BeforeEach(func() {
+					attType = design.Duration
+					min := float64(time.Second)
+					validation = &dslengine.ValidationDefinition{
+						Minimum: &min,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(durationMinValCode))
+				})
+			})
+
+			Context("of enum on raw JSON", func() {
+				BeforeEach(func() {
+					attType = design.RawJSON
+					validation = &dslengine.ValidationDefinition{
+						Values: []interface{}{"{}"},
+					}
+				})
+
+				It("produces no validation", func() {
+					Ω(code).Should(BeEmpty())
+				})
+			})
+
 			Context("of min length 1", func() {
 				BeforeEach(func() {
 					attType = &design.Array{
@@ -87,6 +158,36 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of unique items", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.String,
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueItems: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(uniqueItemsValCode))
+				})
+			})
+
+			Context("of required raw JSON", func() {
+				BeforeEach(func() {
+					attType = design.Object{"doc": &design.AttributeDefinition{Type: design.RawJSON}}
+					validation = &dslengine.ValidationDefinition{
+						Required: []string{"doc"},
+					}
+				})
+
+				It("checks the raw JSON is not empty", func() {
+					Ω(code).Should(Equal(rawJSONRequiredValCode))
+				})
+			})
+
 			Context("of embedded object", func() {
 				BeforeEach(func() {
 					enumVal := &dslengine.ValidationDefinition{
@@ -128,13 +229,15 @@ var _ = Describe("validation code generation", func() {
 
 const (
 	enumValCode = `	if val != nil {
-		if !(*val == 1 || *val == 2 || *val == 3) {
+		switch *val {
+		case 1, 2, 3:
+		default:
 			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`context`" + `, *val, []interface{}{1, 2, 3}))
 		}
 	}`
 
 	patternValCode = `	if val != nil {
-		if ok := goa.ValidatePattern(` + "`.*`" + `, *val); !ok {
+		if ok := pattern9fd4a0c1.MatchString(*val); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`context`" + `, *val, ` + "`.*`" + `))
 		}
 	}`
@@ -145,15 +248,44 @@ const (
 		}
 	}`
 
+	bigIntMinValCode = `	if val != nil {
+		if bound, _ := new(big.Int).SetString("0", 10); val.Cmp(bound) < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, val, 0, true))
+		}
+	}`
+
+	bigIntMaxValCode = `	if val != nil {
+		if bound, _ := new(big.Int).SetString("1000000000000000", 10); val.Cmp(bound) > 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, val, 1e+15, false))
+		}
+	}`
+
+	durationMinValCode = `	if val != nil {
+		if *val < goa.Duration(1e+09) {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 1e+09, true))
+		}
+	}`
+
 	minLengthValCode = `	if val != nil {
 		if len(val) < 1 {
 			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
 		}
 	}`
 
+	uniqueItemsValCode = `	if dup, ok := goa.DuplicateItem(val); ok {
+		err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `context` + "`" + `, dup))
+	}`
+
+	rawJSONRequiredValCode = `	if len(val.Doc) == 0 {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "doc"))
+	}
+`
+
 	embeddedValCode = `	if val.Foo != nil {
 		if val.Foo.Bar != nil {
-			if !(*val.Foo.Bar == 1 || *val.Foo.Bar == 2 || *val.Foo.Bar == 3) {
+			switch *val.Foo.Bar {
+			case 1, 2, 3:
+			default:
 				err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `context.foo.bar` + "`" + `, *val.Foo.Bar, []interface{}{1, 2, 3}))
 			}
 		}
@@ -165,7 +297,9 @@ const (
 
 	if val.Foo != nil {
 		if val.Foo.Bar != nil {
-			if !(*val.Foo.Bar == 1 || *val.Foo.Bar == 2 || *val.Foo.Bar == 3) {
+			switch *val.Foo.Bar {
+			case 1, 2, 3:
+			default:
 				err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `context.foo.bar` + "`" + `, *val.Foo.Bar, []interface{}{1, 2, 3}))
 			}
 		}
@@ -0,0 +1,63 @@
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+)
+
+// accessKey is the name of the metadata that restricts the variants in which an attribute appears,
+// its value is "readonly" or "writeonly".
+const accessKey = "struct:access"
+
+// variantOmits maps the variant modes accepted by GoVariant to the access of the attributes that
+// the variants omit.
+var variantOmits = map[string]string{
+	"create":   "readonly",
+	"response": "writeonly",
+}
+
+// GoVariant produces the Go code that declares the variant of the given object user type for the
+// given mode: the "create" variant omits the attributes whose "struct:access" metadata is
+// "readonly", e.g. identifiers and timestamps set by the server, and the "response" variant omits
+// the attributes whose metadata is "writeonly", e.g. passwords. The variant is named after the
+// type and the mode, e.g. "BottleCreate", and the omitted attributes are removed from its required
+// attributes. Only the attributes of the type itself are filtered, the user types it refers to are
+// used as is. GoVariant panics if the mode is unknown or if the metadata has an invalid value.
+func GoVariant(ut *design.UserTypeDefinition, mode string) string {
+	omit, ok := variantOmits[mode]
+	if !ok {
+		panic(fmt.Sprintf("unknown variant mode %#v, must be \"create\" or \"response\"", mode))
+	}
+	if !ut.IsObject() {
+		panic("goa bug: variants require an object user type")
+	}
+	att := design.DupAtt(ut.AttributeDefinition)
+	o := att.Type.ToObject()
+	for n, catt := range o {
+		access, ok := catt.Metadata[accessKey]
+		if !ok || len(access) == 0 {
+			continue
+		}
+		if access[0] != "readonly" && access[0] != "writeonly" {
+			panic(fmt.Sprintf("invalid %s metadata %#v of attribute %#v of %s, must be \"readonly\" or \"writeonly\"",
+				accessKey, access[0], n, ut.TypeName))
+		}
+		if access[0] == omit {
+			delete(o, n)
+		}
+	}
+	if att.Validation != nil {
+		var required []string
+		for _, n := range att.Validation.Required {
+			if _, ok := o[n]; ok {
+				required = append(required, n)
+			}
+		}
+		att.Validation.Required = required
+	}
+	base := GoTypeName(ut, nil, 0, false)
+	name := base + Goify(mode, true)
+	return fmt.Sprintf("// %s is the %s type without its %s attributes.\ntype %s %s\n",
+		name, base, omit, name, GoTypeDef(att, 0, true, false))
+}
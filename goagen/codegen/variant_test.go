@@ -0,0 +1,64 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoVariant", func() {
+	var ut *design.UserTypeDefinition
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Account",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:access": {"readonly"}},
+					},
+					"name": &design.AttributeDefinition{Type: design.String},
+					"password": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"struct:access": {"writeonly"}},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name", "password"}},
+			},
+		}
+	})
+
+	It("omits the readonly attributes of the create variant", func() {
+		Ω(codegen.GoVariant(ut, "create")).Should(Equal(`// AccountCreate is the Account type without its readonly attributes.
+type AccountCreate struct {
+	Name string ` + "`" + `json:"name" xml:"name"` + "`" + `
+	Password string ` + "`" + `json:"password" xml:"password"` + "`" + `
+}
+`))
+	})
+
+	It("omits the writeonly attributes of the response variant", func() {
+		code := codegen.GoVariant(ut, "response")
+		Ω(code).Should(ContainSubstring("type AccountResponse struct {"))
+		Ω(code).Should(ContainSubstring("\tID int "))
+		Ω(code).ShouldNot(ContainSubstring("Password"))
+	})
+
+	It("leaves the type unchanged", func() {
+		codegen.GoVariant(ut, "create")
+		Ω(ut.Type.ToObject()).Should(HaveKey("id"))
+		Ω(ut.Validation.Required).Should(Equal([]string{"id", "name", "password"}))
+	})
+
+	It("rejects unknown modes", func() {
+		Ω(func() { codegen.GoVariant(ut, "update") }).Should(Panic())
+	})
+
+	It("rejects invalid access metadata", func() {
+		ut.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{"struct:access": {"hidden"}}
+		Ω(func() { codegen.GoVariant(ut, "create") }).Should(Panic())
+	})
+})
@@ -250,9 +250,7 @@ func (f *SourceFile) FormatCode() error {
 	file, err := parser.ParseFile(fset, f.Abs(), nil, parser.ParseComments)
 	if err != nil {
 		content, _ := ioutil.ReadFile(f.Abs())
-		var buf bytes.Buffer
-		scanner.PrintError(&buf, err)
-		return fmt.Errorf("%s\n========\nContent:\n%s", buf.String(), content)
+		return syntaxError(err, content)
 	}
 	// Clean unused imports
 	imports := astutil.Imports(fset, file)
@@ -370,3 +368,47 @@ const (
 {{if gt (len .Imports) 1}})
 {{end}}{{end}}`
 )
+
+// FormatCode runs gofmt on the given generated source code. If the code does not parse the
+// returned error lists each syntax error together with the surrounding lines of code so that
+// template bugs surface as readable parse errors rather than as compilation failures of the
+// generated package.
+func FormatCode(src []byte) ([]byte, error) {
+	res, err := format.Source(src)
+	if err != nil {
+		return nil, syntaxError(err, src)
+	}
+	return res, nil
+}
+
+// syntaxError builds an error that describes the syntax errors in err with the lines of src
+// around each error, the line where the error occurred is marked with ">".
+func syntaxError(err error, src []byte) error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return err
+	}
+	lines := strings.Split(string(src), "\n")
+	var buf bytes.Buffer
+	for i, e := range list {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(e.Error())
+		from, to := e.Pos.Line-2, e.Pos.Line+2
+		if from < 1 {
+			from = 1
+		}
+		if to > len(lines) {
+			to = len(lines)
+		}
+		for l := from; l <= to; l++ {
+			marker := " "
+			if l == e.Pos.Line {
+				marker = ">"
+			}
+			fmt.Fprintf(&buf, "\n%s %4d | %s", marker, l, lines[l-1])
+		}
+	}
+	return fmt.Errorf("%s", buf.String())
+}
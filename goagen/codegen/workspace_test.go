@@ -0,0 +1,25 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FormatCode", func() {
+	It("formats valid code", func() {
+		src := "package foo\nfunc  Bar( ) int {\nreturn 42}\n"
+		res, err := codegen.FormatCode([]byte(src))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(res)).Should(Equal("package foo\n\nfunc Bar() int {\n\treturn 42\n}\n"))
+	})
+
+	It("reports syntax errors with the surrounding lines", func() {
+		src := "package foo\n\nfunc Bar() int {\n\treturn 42 +\n}\n\nfunc Baz() {}\n"
+		_, err := codegen.FormatCode([]byte(src))
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(HavePrefix("5:1: expected operand"))
+		Ω(err.Error()).Should(ContainSubstring("\n     4 | \treturn 42 +\n>    5 | }\n     6 | "))
+		Ω(err.Error()).ShouldNot(ContainSubstring("package foo"))
+	})
+})
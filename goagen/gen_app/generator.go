@@ -102,6 +102,9 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 	if err := g.generateUserTypes(api); err != nil {
 		return nil, err
 	}
+	if err := g.generatePatterns(api); err != nil {
+		return nil, err
+	}
 	if !NoGenTest {
 		if err := g.generateResourceTest(api); err != nil {
 			return nil, err
@@ -161,6 +164,8 @@ func (g *Generator) generateContexts(api *design.APIDefinition) error {
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
@@ -521,6 +526,8 @@ func (g *Generator) generateMediaTypes(api *design.APIDefinition) error {
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
 	mtWr.WriteHeader(title, TargetPackage, imports)
@@ -553,6 +560,8 @@ func (g *Generator) generateUserTypes(api *design.APIDefinition) error {
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("encoding/json"),
 	}
 	utWr.WriteHeader(title, TargetPackage, imports)
 	err = api.IterateUserTypes(func(t *design.UserTypeDefinition) error {
@@ -564,3 +573,46 @@ func (g *Generator) generateUserTypes(api *design.APIDefinition) error {
 	}
 	return utWr.FormatCode()
 }
+
+// generatePatterns generates the package level variables holding the compiled regular expressions
+// used by the pattern validations of the contexts, media types and user types.
+func (g *Generator) generatePatterns(api *design.APIDefinition) error {
+	var atts []*design.AttributeDefinition
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			atts = append(atts, a.AllParams(), r.Headers, a.Headers)
+			if a.Payload != nil {
+				atts = append(atts, a.Payload.AttributeDefinition)
+			}
+			return nil
+		})
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		atts = append(atts, mt.AttributeDefinition)
+		return nil
+	})
+	api.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		atts = append(atts, t.AttributeDefinition)
+		return nil
+	})
+	patterns := codegen.Patterns(atts...)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	patFile := filepath.Join(AppOutputDir(), "validation.go")
+	patWr, err := codegen.SourceFileFor(patFile)
+	if err != nil {
+		panic(err) // bug
+	}
+	title := fmt.Sprintf("%s: Application Validation Patterns", api.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("regexp"),
+	}
+	patWr.WriteHeader(title, TargetPackage, imports)
+	g.genfiles = append(g.genfiles, patFile)
+	if _, err := patWr.Write([]byte(codegen.GoPatternVars(patterns))); err != nil {
+		return err
+	}
+	return patWr.FormatCode()
+}
@@ -455,7 +455,33 @@ type {{ .Name }} struct {
 */}}{{ if .Pointer }}{{ $tmp := tempvar }}{{ tabs .Depth }}{{ $tmp }} := interface{}(raw{{ goify .Name true }})
 {{ tabs .Depth }}{{ .Pkg }} = &{{ $tmp }}
 {{ else }}{{ tabs .Depth }}{{ .Pkg }} = raw{{ goify .Name true }}
-{{ end }}{{ end }}{{ if eq .Attribute.Type.Kind 8 }}{{/*
+{{ end }}{{ end }}{{ if eq .Attribute.Type.Kind 13 }}{{/*
+
+*/}}{{/* BigIntType */}}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, ok := new(big.Int).SetString(raw{{ goify .Name true }}, 10); ok {
+{{ tabs .Depth }}	{{ .Pkg }} = {{ .VarName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "integer"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 14 }}{{/*
+
+*/}}{{/* DurationType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDuration(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "duration"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 15 }}{{/*
+
+*/}}{{/* RawJSONType */}}{{/*
+*/}}{{ tabs .Depth }}if json.Valid([]byte(raw{{ goify .Name true }})) {
+{{ tabs .Depth }}	{{ .Pkg }} = json.RawMessage(raw{{ goify .Name true }})
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "JSON"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 8 }}{{/*
 
 */}}{{/* ArrayType */}}{{/*
 */}}{{ tabs .Depth }}elems{{ goify .Name true }} := strings.Split(raw{{ goify .Name true }}, ",")
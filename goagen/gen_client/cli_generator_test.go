@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
@@ -146,6 +147,60 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with an action with big integer, duration and raw JSON parameters", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								QueryParams: &design.AttributeDefinition{
+									Type: design.Object{
+										"count":   &design.AttributeDefinition{Type: design.BigInt},
+										"timeout": &design.AttributeDefinition{Type: design.Duration, DefaultValue: time.Second},
+										"filter":  &design.AttributeDefinition{Type: design.RawJSON},
+									},
+								},
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("generates string flags", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(7))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "testapi-cli", "commands.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("var count string"))
+			Ω(content).Should(ContainSubstring(`cc.Flags().StringVar(&cmd.Count, "count", count,`))
+			Ω(content).Should(ContainSubstring(`cc.Flags().StringVar(&cmd.Timeout, "timeout", "1s",`))
+			Ω(content).Should(ContainSubstring(`cc.Flags().StringVar(&cmd.Filter, "filter", filter,`))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("count string, filter string, timeout string)"))
+			_, err = gexec.Build(filepath.Join(testgenPackagePath, "client", "testapi-cli"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
 	Context("with an action with security configured", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -1,6 +1,7 @@
 package genclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -91,6 +92,8 @@ func (g *Generator) generateClientResources(clientPkg string, funcs template.Fun
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
 	if err := file.WriteHeader("User Types", "client", imports); err != nil {
@@ -191,6 +194,8 @@ func (g *Generator) generateResourceClient(res *design.ResourceDefinition, funcs
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
@@ -215,14 +220,6 @@ func (g *Generator) generateResourceClient(res *design.ResourceDefinition, funcs
 			}
 			action.QueryParams.Type = params
 		}
-		if action.Headers != nil {
-			headers := make(design.Object, len(action.Headers.Type.ToObject()))
-			for n, header := range action.Headers.Type.ToObject() {
-				name := codegen.Goify(n, false)
-				headers[name] = header
-			}
-			action.Headers.Type = headers
-		}
 		if action.WebSocket() {
 			return clientsWSTmpl.Execute(file, action)
 		}
@@ -272,6 +269,7 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		"cmdFieldType":    cmdFieldType,
 		"defaultPath":     defaultPath,
 		"escapeBackticks": escapeBackticks,
+		"flagDefault":     flagDefault,
 		"flagType":        flagType,
 		"goify":           codegen.Goify,
 		"gotypedef":       codegen.GoTypeDef,
@@ -385,13 +383,37 @@ func goTypeRefExt(t design.DataType, tabs int, pkg string) string {
 }
 
 // cmdFieldType computes the Go type name used to store command flags of the given design type.
+// Values that the API parses from their string representation (date times, UUIDs, big integers,
+// durations and raw JSON) are kept as strings and sent as is.
 func cmdFieldType(t design.DataType) string {
-	if t.Kind() == design.DateTimeKind || t.Kind() == design.UUIDKind {
+	switch t.Kind() {
+	case design.DateTimeKind, design.UUIDKind, design.BigIntKind, design.DurationKind, design.RawJSONKind:
 		return "string"
+	case design.ArrayKind:
+		return "[]" + cmdFieldType(t.ToArray().ElemType.Type)
 	}
 	return codegen.GoNativeType(t)
 }
 
+// flagDefault returns the Go literal used as default value of the command flag for the given
+// attribute. Default values of types stored as strings by cmdFieldType are rendered as strings.
+func flagDefault(att *design.AttributeDefinition) string {
+	switch att.Type.Kind() {
+	case design.BigIntKind, design.DurationKind:
+		return fmt.Sprintf("%q", fmt.Sprintf("%v", att.DefaultValue))
+	case design.RawJSONKind:
+		if raw, ok := att.DefaultValue.(json.RawMessage); ok {
+			return fmt.Sprintf("%q", string(raw))
+		}
+		b, err := json.Marshal(att.DefaultValue)
+		if err != nil {
+			panic(fmt.Sprintf("invalid raw JSON default value %#v", att.DefaultValue)) // bug
+		}
+		return fmt.Sprintf("%q", string(b))
+	}
+	return fmt.Sprintf("%#v", att.DefaultValue)
+}
+
 // template used to produce code that serializes arrays of simple values into comma separated
 // strings.
 var arrayToStringTmpl *template.Template
@@ -407,7 +429,7 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			return fmt.Sprintf("%s := strconv.FormatBool(%s)", target, name)
 		case design.NumberKind:
 			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
-		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+		case design.StringKind, design.DateTimeKind, design.UUIDKind, design.BigIntKind, design.DurationKind, design.RawJSONKind:
 			return fmt.Sprintf("%s := %s", target, name)
 		case design.AnyKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
@@ -443,6 +465,8 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.AnyKind:
 		return "String"
+	case design.BigIntKind, design.DurationKind, design.RawJSONKind:
+		return "String"
 	case design.ArrayKind:
 		return flagType(att.Type.(*design.Array).ElemType) + "Slice"
 	case design.UserTypeKind:
@@ -550,7 +574,7 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Payload
 	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}) (*http.Response, error) {
 	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .Payload }}, payload {{ end }}{{/*
 */}}{{ $params := .QueryParams }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}, {{ goify $name false }}{{ end }}{{ end }}{{/*
-*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }})
+*/}}{{ $headers := .Headers }}{{ if $headers }}{{ range $name, $att := $headers.Type.ToObject }}, {{ goify $name false }}{{ end }}{{ end }})
 	if err != nil {
 		return nil, err
 	}
@@ -586,7 +610,7 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Payload
 		return nil, err
 	}
 {{ $headers := .Headers }}	header := req.Header
-{{ if $headers }}{{ range $name, $att := $params.Type.ToObject }}{{ if (eq $att.Type.Kind 4) }}	header.Set("{{ $name }}", {{ goify $name false }})
+{{ if $headers }}{{ range $name, $att := $headers.Type.ToObject }}{{ if (eq $att.Type.Kind 4) }}	header.Set("{{ $name }}", {{ goify $name false }})
 {{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
 	header.Set("{{ $name }}", {{ $tmp }})
 {{ end }}{{ end }}{{ end }}	header.Set("Content-Type", "application/json"){{ if .Security }}
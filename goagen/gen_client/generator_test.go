@@ -85,6 +85,56 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with an action with headers", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								QueryParams: &design.AttributeDefinition{
+									Type: design.Object{
+										"page": &design.AttributeDefinition{Type: design.Integer},
+									},
+								},
+								Headers: &design.AttributeDefinition{
+									Type: design.Object{
+										"X-Name": &design.AttributeDefinition{Type: design.String},
+										"X-Wait": &design.AttributeDefinition{Type: design.Duration},
+									},
+								},
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("passes the header values to the request and sets the headers", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("c.NewShowFooRequest(ctx, path, page, xName, xWait)"))
+			Ω(content).Should(ContainSubstring(`header.Set("X-Name", xName)`))
+			Ω(content).Should(ContainSubstring(`header.Set("X-Wait", tmp`))
+			Ω(content).ShouldNot(ContainSubstring(`header.Set("page"`))
+		})
+	})
+
 	Context("with an action with security configured", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
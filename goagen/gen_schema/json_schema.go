@@ -40,6 +40,7 @@ type (
 		Maximum              float64       `json:"maximum,omitempty"`
 		MinLength            int           `json:"minLength,omitempty"`
 		MaxLength            int           `json:"maxLength,omitempty"`
+		UniqueItems          bool          `json:"uniqueItems,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
 
@@ -320,6 +321,7 @@ func (s *JSONSchema) Merge(other *JSONSchema) {
 		{&s.Maximum, other.Maximum, s.Maximum < other.Maximum},
 		{&s.MinLength, other.MinLength, s.MinLength > other.MinLength},
 		{&s.MaxLength, other.MaxLength, s.MaxLength < other.MaxLength},
+		{&s.UniqueItems, other.UniqueItems, s.UniqueItems == false},
 	} {
 		if v.needed && v.b != nil {
 			reflect.Indirect(reflect.ValueOf(v.a)).Set(reflect.ValueOf(v.b))
@@ -371,6 +373,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Maximum:              s.Maximum,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
+		UniqueItems:          s.UniqueItems,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
 	}
@@ -415,6 +418,7 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	if val.MaxLength != nil {
 		s.MaxLength = *val.MaxLength
 	}
+	s.UniqueItems = val.UniqueItems
 	s.Required = val.Required
 	return s
 }
@@ -866,6 +866,17 @@ func initMaxLengthValidation(def interface{}, isArray bool, max int) {
 	}
 }
 
+func initUniqueItemsValidation(def interface{}, unique bool) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.UniqueItems = unique
+	case *Header:
+		actual.UniqueItems = unique
+	case *Items:
+		actual.UniqueItems = unique
+	}
+}
+
 func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	val := attr.Validation
 	if val == nil {
@@ -886,4 +897,7 @@ func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	if val.MaxLength != nil {
 		initMaxLengthValidation(def, attr.Type.IsArray(), *val.MaxLength)
 	}
+	if val.UniqueItems {
+		initUniqueItemsValidation(def, true)
+	}
 }
@@ -373,6 +373,7 @@ var _ = Describe("New", func() {
 								// interpreted as MinItems & MaxItems:
 								MinLength(1)
 								MaxLength(5)
+								UniqueItems()
 							})
 							Header("OverrideRequiredHeader")
 							Header("OverrideOptionalHeader")
@@ -410,7 +411,7 @@ var _ = Describe("New", func() {
 				// check Headers in detail
 				Ω(ps[3]).Should(Equal(&genswagger.Parameter{In: "header", Name: "Authorization", Type: "string", Required: true}))
 				Ω(ps[4]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalArray", Type: "array",
-					Items: &genswagger.Items{Type: "string"}, MinItems: 1, MaxItems: 5}))
+					Items: &genswagger.Items{Type: "string"}, MinItems: 1, MaxItems: 5, UniqueItems: true}))
 				Ω(ps[5]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalBoolWithDefault", Type: "boolean",
 					Description: "defaults true", Default: true}))
 				Ω(ps[6]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalInt", Type: "integer", Minimum: -2, Maximum: 2}))
@@ -68,6 +68,10 @@ type (
 
 	// DecodeFunc is the function that initialize the unmarshaled payload from the request body.
 	DecodeFunc func(context.Context, io.ReadCloser, interface{}) error
+
+	// Endpoint exposes a service method as a function that accepts the decoded payload and
+	// returns the method result.
+	Endpoint func(ctx context.Context, req interface{}) (interface{}, error)
 )
 
 // New instantiates a service with the given name.
@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/mail"
 	"net/url"
+	"reflect"
 	"regexp"
 	"time"
 
@@ -123,3 +124,35 @@ func ValidatePattern(p string, val string) bool {
 	}
 	return r.MatchString(val)
 }
+
+// DuplicateItem returns the first item of the given slice that is equal to an item that precedes
+// it and true, nil and false if the items are unique or if val is not a slice. Items of comparable
+// types are compared with ==, the others, including pointers and interfaces, are compared with
+// reflect.DeepEqual so that objects are compared by value.
+func DuplicateItem(val interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elem := v.Type().Elem()
+	if elem.Comparable() && elem.Kind() != reflect.Ptr && elem.Kind() != reflect.Interface {
+		seen := make(map[interface{}]struct{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i).Interface()
+			if _, ok := seen[item]; ok {
+				return item, true
+			}
+			seen[item] = struct{}{}
+		}
+		return nil, false
+	}
+	for i := 1; i < v.Len(); i++ {
+		item := v.Index(i).Interface()
+		for j := 0; j < i; j++ {
+			if reflect.DeepEqual(v.Index(j).Interface(), item) {
+				return item, true
+			}
+		}
+	}
+	return nil, false
+}
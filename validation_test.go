@@ -287,3 +287,37 @@ var _ = Describe("ValidateFormat", func() {
 
 	})
 })
+
+var _ = Describe("DuplicateItem", func() {
+	type item struct {
+		Name string
+	}
+
+	It("returns the first duplicate item", func() {
+		dup, ok := goa.DuplicateItem([]string{"a", "b", "c", "b", "a"})
+		Ω(ok).Should(BeTrue())
+		Ω(dup).Should(Equal("b"))
+	})
+
+	It("compares pointers by value", func() {
+		dup, ok := goa.DuplicateItem([]*item{{Name: "a"}, {Name: "b"}, {Name: "a"}})
+		Ω(ok).Should(BeTrue())
+		Ω(dup).Should(Equal(&item{Name: "a"}))
+	})
+
+	It("compares slices by value", func() {
+		_, ok := goa.DuplicateItem([][]int{{1, 2}, {2, 1}})
+		Ω(ok).Should(BeFalse())
+		_, ok = goa.DuplicateItem([][]int{{1, 2}, {1, 2}})
+		Ω(ok).Should(BeTrue())
+	})
+
+	It("accepts unique items and non slice values", func() {
+		_, ok := goa.DuplicateItem([]int{1, 2, 3})
+		Ω(ok).Should(BeFalse())
+		_, ok = goa.DuplicateItem([]interface{}{map[string]interface{}{"a": 1}, map[string]interface{}{"a": 2}})
+		Ω(ok).Should(BeFalse())
+		_, ok = goa.DuplicateItem(nil)
+		Ω(ok).Should(BeFalse())
+	})
+})